@@ -1,10 +1,10 @@
 package wsmock
 
 import (
-	"fmt"
 	"reflect"
 	"regexp"
 	"runtime"
+	"time"
 )
 
 // Assertions are ordered chains of conditions.
@@ -18,8 +18,28 @@ import (
 // When several Assertion structs are created on the same recorder, they are run independently from each other.
 type Assertion struct {
 	conditions []Condition
+	mode       assertionMode
+	window     time.Duration              // see EventuallyAssertion.Within and ConsistentlyAssertion.For
+	filter     Predicate                  // see Filter
+	decode     func(msg any) (any, error) // see TypedRecorder
 }
 
+// jobs use views of the round log, unless messages are filtered or decoded
+func (a *Assertion) usesLog() bool {
+	return a.filter == nil && a.decode == nil
+}
+
+type assertionMode int
+
+const (
+	// conditions are chained, the assertion succeeds as soon as all of them passed
+	chainMode assertionMode = iota
+	// see Recorder.Eventually
+	eventuallyMode
+	// see Recorder.Consistently
+	consistentlyMode
+)
+
 func getFunctionName(f Predicate) string {
 	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
 }
@@ -37,178 +57,293 @@ func (a *Assertion) With(f ConditionFunc) *Assertion {
 	return a.append(f)
 }
 
+// Adds a Condition to the assertion (built-in conditions can be created with constructors like OneToBe)
+func (a *Assertion) WithCondition(c Condition) *Assertion {
+	return a.append(c)
+}
+
+// Scopes the assertion to the messages checking the Predicate: other messages are ignored by all of its conditions
+// (for instance NextToBe applies to the next message checking the Predicate). Filter can be called anywhere in the
+// chain, it applies to the whole assertion, and several calls are combined with And.
+func (a *Assertion) Filter(f Predicate) *Assertion {
+	if a.filter == nil {
+		a.filter = f
+	} else {
+		a.filter = And(a.filter, f)
+	}
+	return a
+}
+
+// Time windows
+
+// Sets a time window on the last added condition: it fails if it is not done within d after it becomes
+// the current condition (that is to say after the previous condition passed, or when the assertion starts
+// for the first condition). When the window is over, the condition is evaluated as if the end was reached,
+// meaning for instance that a NoneToBe condition succeeds.
+//
+// Time windows are not supported on structural steps (Either, Optional, Repeat, ZeroOrMore, OneOrMore and Until):
+// the assertion fails if Within follows one of them.
+func (a *Assertion) Within(d time.Duration) *Assertion {
+	if len(a.conditions) > 0 {
+		last := len(a.conditions) - 1
+		a.conditions[last] = newWithin(a.conditions[last], d)
+	}
+	return a
+}
+
+// Adds a condition that succeeds if no message is received during d, and fails as soon as one is
+func (a *Assertion) NoneWithin(d time.Duration) *Assertion {
+	return a.append(NoneWithin(d))
+}
+
+// Branching
+
+// Adds a step that succeeds if the conditions added by one of the branches pass, the assertion then goes on from
+// this branch. Branches are tried concurrently on the same messages.
+//
+// For instance `rec.NewAssertion().OneToBe("req").Either(func(a *Assertion) { a.NextToBe("ok").OneToBe("result") }, func(a *Assertion) { a.NextToBe("error") })`
+// succeeds with `req ok result` and with `req error`.
+func (a *Assertion) Either(branches ...func(a *Assertion)) *Assertion {
+	e := &either{}
+	for _, f := range branches {
+		branch := &Assertion{}
+		f(branch)
+		e.branches = append(e.branches, branch.conditions)
+	}
+	return a.append(e)
+}
+
+// Adds a step that may be skipped: the assertion goes on both with and without c passing.
+//
+// For instance `rec.NewAssertion().OneToBe("start").Optional(NextToBe("warning")).OneToBe("end")` succeeds with
+// `start end` and with `start warning end`.
+func (a *Assertion) Optional(c Condition) *Assertion {
+	return a.append(&optional{c})
+}
+
+// Repetition
+
+// Adds a step that succeeds if c passes n times in a row (each time on messages received after the previous pass)
+func (a *Assertion) Repeat(n int, c Condition) *Assertion {
+	return a.append(&repeat{n, c})
+}
+
+// Adds a step that succeeds if any number of next messages (including none) check the Predicate
+func (a *Assertion) ZeroOrMore(f Predicate) *Assertion {
+	return a.append(&zeroOrMore{f})
+}
+
+// Adds a step that succeeds if at least one next message checks the Predicate
+func (a *Assertion) OneOrMore(f Predicate) *Assertion {
+	return a.append(&oneOrMore{f})
+}
+
+// Adds a step that skips any messages until one checks the Predicate. Contrary to OneToCheck, the assertion goes on
+// from every message checking the Predicate, not only the first one.
+//
+// For instance `rec.NewAssertion().Until(isDone).NextToBe("bye")` succeeds with `done done bye`, whereas
+// `rec.NewAssertion().OneToCheck(isDone).NextToBe("bye")` fails.
+func (a *Assertion) Until(f Predicate) *Assertion {
+	return a.append(&until{f})
+}
+
 // OneTo*
 
 // Adds a condition that succeeds if a new message is equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) OneToBe(target any) *Assertion {
-	return a.append(newOneTo(eq(target), fmt.Sprintf("[OneToBe] no message is equal to: %#v", target)))
+	return a.append(OneToBe(target))
 }
 
 // Adds a condition that succeeds if a new message checks the Predicate
 func (a *Assertion) OneToCheck(f Predicate) *Assertion {
-	return a.append(newOneTo(f, fmt.Sprintf("[OneToCheck] no message checks predicate: %v", getFunctionName(f))))
+	return a.append(OneToCheck(f))
 }
 
 // Adds a condition that succeeds if a new message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) OneToContain(sub string) *Assertion {
-	return a.append(newOneTo(contain(sub), fmt.Sprintf("[OneToContain] no message contains string: %v", sub)))
+	return a.append(OneToContain(sub))
 }
 
 // Adds a condition that succeeds if a new message matches the regular expression
 func (a *Assertion) OneToMatch(re *regexp.Regexp) *Assertion {
-	return a.append(newOneTo(match(re), fmt.Sprintf("[OneToMatch] no message matches regexp: %v", re)))
+	return a.append(OneToMatch(re))
 }
 
 // OneNot*
 
 // Adds a condition that succeeds if a new message is not equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) OneNotToBe(target any) *Assertion {
-	return a.append(newOneTo(not(eq(target)), fmt.Sprintf("[OneNotToBe] message unexpectedly equal to: %#v", target)))
+	return a.append(OneNotToBe(target))
 }
 
 // Adds a condition that succeeds if a new message does not check the Predicate
 func (a *Assertion) OneNotToCheck(f Predicate) *Assertion {
-	return a.append(newOneTo(not(f), fmt.Sprintf("[OneNotToCheck] message unexpectedly checks predicate: %v", getFunctionName(f))))
+	return a.append(OneNotToCheck(f))
 }
 
 // Adds a condition that succeeds if a new message does not contain the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) OneNotToContain(sub string) *Assertion {
-	return a.append(newOneTo(not(contain(sub)), fmt.Sprintf("[OneNotToContain] message unexpectedly contains string: %v", sub)))
+	return a.append(OneNotToContain(sub))
 }
 
 // Adds a condition that succeeds if a new message does not match the regular expression
 func (a *Assertion) OneNotToMatch(re *regexp.Regexp) *Assertion {
-	return a.append(newOneTo(not(match(re)), fmt.Sprintf("[OneNotToMatch] message unexpectedly matches regexp: %v", re)))
+	return a.append(OneNotToMatch(re))
 }
 
 // NextTo*
 
 // Adds a condition that succeeds if the next message is equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) NextToBe(target any) *Assertion {
-	return a.append(newNextTo(eq(target), fmt.Sprintf("[NextToBe] next message is not equal to: %#v", target)))
+	return a.append(NextToBe(target))
 }
 
 // Adds a condition that succeeds if the next message checks the Predicate
 func (a *Assertion) NextToCheck(f Predicate) *Assertion {
-	return a.append(newNextTo(f, fmt.Sprintf("[NextToCheck] next message does not check predicate: %v", getFunctionName(f))))
+	return a.append(NextToCheck(f))
 }
 
 // Adds a condition that succeeds if the next message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) NextToContain(sub string) *Assertion {
-	return a.append(newNextTo(contain(sub), fmt.Sprintf("[NextToContain] next message does not contain string: %v", sub)))
+	return a.append(NextToContain(sub))
 }
 
 // Adds a condition that succeeds if the next message matches the regular expression
 func (a *Assertion) NextToMatch(re *regexp.Regexp) *Assertion {
-	return a.append(newNextTo(match(re), fmt.Sprintf("[NextToMatch] next message does not match regexp: %v", re)))
+	return a.append(NextToMatch(re))
 }
 
 // NextNot*
 
 // Adds a condition that succeeds if the next message is not equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) NextNotToBe(target any) *Assertion {
-	return a.append(newNextTo(not(eq(target)), fmt.Sprintf("[NextNotToBe] next message unexpectedly equal to: %#v", target)))
+	return a.append(NextNotToBe(target))
 }
 
 // Adds a condition that succeeds if the next message does not check the Predicate
 func (a *Assertion) NextNotToCheck(f Predicate) *Assertion {
-	return a.append(newNextTo(not(f), fmt.Sprintf("[NextNotToCheck] next message unexpectedly checks predicate: %v", getFunctionName(f))))
+	return a.append(NextNotToCheck(f))
 }
 
 // Adds a condition that succeeds if the next message does not contain the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) NextNotToContain(sub string) *Assertion {
-	return a.append(newNextTo(not(contain(sub)), fmt.Sprintf("[NextNotToContain] next message unexpectedly contains string: %v", sub)))
+	return a.append(NextNotToContain(sub))
 }
 
 // Adds a condition that succeeds if the next message does not match the regular expression
 func (a *Assertion) NextNotToMatch(re *regexp.Regexp) *Assertion {
-	return a.append(newNextTo(not(match(re)), fmt.Sprintf("[NextNotToMatch] next message unexpectedly matches regexp: %v", re)))
+	return a.append(NextNotToMatch(re))
+}
+
+// Unordered
+
+// Adds a condition that succeeds once each of the given interfaces is equal to a distinct new message, in any order (according to the equality operator `==`)
+func (a *Assertion) SetToBe(targets ...any) *Assertion {
+	return a.append(SetToBe(targets...))
+}
+
+// Adds a condition that succeeds once each of the given Predicates is checked by a distinct new message, in any order
+func (a *Assertion) AllOf(fs ...Predicate) *Assertion {
+	return a.append(AllOf(fs...))
+}
+
+// Timing
+
+// Adds a condition that succeeds if a new message checking replyF is written at most max after the latest message
+// sent to the conn (with GorillaConn.Send) checking sentF
+func (a *Assertion) LatencyFrom(sentF, replyF Predicate, max time.Duration) *Assertion {
+	return a.append(LatencyFrom(sentF, replyF, max))
+}
+
+// Adds a condition that succeeds if the time intervals between consecutive remaining messages checking the Predicate are between min and max
+func (a *Assertion) IntervalBetween(f Predicate, min, max time.Duration) {
+	a.append(IntervalBetween(f, min, max))
 }
 
 // Last*
 
 // Adds a condition that succeeds if the last message is equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) LastToBe(target any) {
-	a.append(newLastTo(eq(target), fmt.Sprintf("[LastToBe] last message is not equal to: %#v", target)))
+	a.append(LastToBe(target))
 }
 
 // Adds a condition that succeeds if the last message checks the Predicate
 func (a *Assertion) LastToCheck(f Predicate) {
-	a.append(newLastTo(f, fmt.Sprintf("[LastToCheck] last message deos not check predicate: %v", getFunctionName(f))))
+	a.append(LastToCheck(f))
 }
 
 // Adds a condition that succeeds if the last message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) LastToContain(sub string) {
-	a.append(newLastTo(contain(sub), fmt.Sprintf("[LastToContain] last message does not contain string: %v", sub)))
+	a.append(LastToContain(sub))
 }
 
 // Adds a condition that succeeds if the last message matches the regular expression
 func (a *Assertion) LastToMatch(re *regexp.Regexp) {
-	a.append(newLastTo(match(re), fmt.Sprintf("[LastToMatch] last message does not match regexp: %v", re)))
+	a.append(LastToMatch(re))
 }
 
 // LastNot*
 
 // Adds a condition that succeeds if the last message is not equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) LastNotToBe(target any) {
-	a.append(newLastTo(not(eq(target)), fmt.Sprintf("[LastNotToBe] last message unexpectedly equal to: %#v", target)))
+	a.append(LastNotToBe(target))
 }
 
 // Adds a condition that succeeds if the last message does not check the Predicate
 func (a *Assertion) LastNotToCheck(f Predicate) {
-	a.append(newLastTo(not(f), fmt.Sprintf("[LastNotToCheck] last message unexpectedly checks predicate: %v", getFunctionName(f))))
+	a.append(LastNotToCheck(f))
 }
 
 // Adds a condition that succeeds if the last message does not contain the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) LastNotToContain(sub string) {
-	a.append(newLastTo(not(contain(sub)), fmt.Sprintf("[LastNotToContain] last message unexpectedly contains string: %v", sub)))
+	a.append(LastNotToContain(sub))
 }
 
 // Adds a condition that succeeds if the last message does not match the regular expression
 func (a *Assertion) LastNotToMatch(re *regexp.Regexp) {
-	a.append(newLastTo(not(match(re)), fmt.Sprintf("[LastNotToMatch] last message unexpectedly matches regexp: %v", re)))
+	a.append(LastNotToMatch(re))
 }
 
 // All*
 
 // Adds a condition that succeeds if all remaining messages are equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) AllToBe(target any) {
-	a.append(newAllTo(eq(target), fmt.Sprintf("[AllToBe] message is not equal to: %#v", target)))
+	a.append(AllToBe(target))
 }
 
 // Adds a condition that succeeds if all remaining messages check the Predicate
 func (a *Assertion) AllToCheck(f Predicate) {
-	a.append(newAllTo(f, fmt.Sprintf("[AllToCheck] message does not check predicate: %v", getFunctionName(f))))
+	a.append(AllToCheck(f))
 }
 
 // Adds a condition that succeeds if all remaining messages contain the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) AllToContain(sub string) {
-	a.append(newAllTo(contain(sub), fmt.Sprintf("[AllToContain] message does not contain string: %v", sub)))
+	a.append(AllToContain(sub))
 }
 
 // Adds a condition that succeeds if all remaining messages match the regular expression
 func (a *Assertion) AllToMatch(re *regexp.Regexp) {
-	a.append(newAllTo(match(re), fmt.Sprintf("[AllToMatch] message does not match regexp: %v", re)))
+	a.append(AllToMatch(re))
 }
 
 // None*
 
 // Adds a condition that succeeds if no remaining message is equal to the given interface (according to the equality operator `==`)
 func (a *Assertion) NoneToBe(target any) {
-	a.append(newAllTo(not(eq(target)), fmt.Sprintf("[NoneToBe] message unexpectedly equal to: %#v", target)))
+	a.append(NoneToBe(target))
 }
 
 // Adds a condition that succeeds if no remaining message checks the Predicate
 func (a *Assertion) NoneToCheck(f Predicate) {
-	a.append(newAllTo(not(f), fmt.Sprintf("[NoneToCheck] message unexpectedly checks predicate: %v", getFunctionName(f))))
+	a.append(NoneToCheck(f))
 }
 
 // Adds a condition that succeeds if no remaining message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
 func (a *Assertion) NoneToContain(sub string) {
-	a.append(newAllTo(not(contain(sub)), fmt.Sprintf("[NoneToContain] message unexpectedly contains string: %v", sub)))
+	a.append(NoneToContain(sub))
 }
 
 // Adds a condition that succeeds if no remaining message matches the regular expression
 func (a *Assertion) NoneToMatch(re *regexp.Regexp) {
-	a.append(newAllTo(not(match(re)), fmt.Sprintf("[NoneToMatch] message unexpectedly matches regexp: %v", re)))
+	a.append(NoneToMatch(re))
 }
@@ -7,16 +7,48 @@ import (
 
 type assertionJob struct {
 	rec   *Recorder
-	index int // used in logs
+	round *round // the round of the recorder the job belongs to
+	index int    // used in logs
 	// configuration
-	a *Assertion
-	// events
-	writeCh chan any
-	// message writes history
-	writes []any
+	a     *Assertion
+	prog  []instruction
+	group *groupAssertion // if the assertion is shared by a RecorderGroup
+	// message writes history (records contain the same messages, with timestamps), restricted to
+	// the messages checking the assertion filter if any (and decoded, see TypedRecorder). Without filter
+	// nor decoding, they are views of the round log.
+	writes  []any
+	records []Record
+	indexes []int // positions of the filtered messages in the round
+	// all message writes (see Assertion.Filter)
+	total []Record
+	// deadlines
+	timeoutAt   time.Time
+	windowEndAt time.Time // zero if the assertion has no time window (see Eventually and Consistently)
+	windowAt    time.Time // time of the timer being processed
+	// errors kept for the group report, instead of being added to the recorder
+	errors []string
 	// state
-	done         bool // means finished, as a success OR failure
-	currentIndex int
+	done      bool      // means finished, as a success OR failure
+	threads   []*thread // candidate positions in the program
+	explained []int     // indexes of the messages explained by the conditions, once the assertion passed (see Recorder.Strict)
+	failure   failure   // most advanced failure, reported if no thread is left
+	held      bool      // in consistently mode, the assertion passed and has to hold until the end
+	passed    bool      // means finished as a success
+	event     string    // latest event processed by the job (used in logs)
+}
+
+// A thread is a candidate position in the assertion program, waiting for its condition to be done
+type thread struct {
+	pc        int
+	c         Condition // active instance of the condition at pc
+	deadline  time.Time // zero if the condition has no time window
+	start     int       // index of the first message the condition is tried on
+	explained []int     // indexes of the messages explained by the previous conditions
+}
+
+type failure struct {
+	step    int
+	err, on string
 }
 
 func last[T any](slice []T) (T, bool) {
@@ -28,103 +60,357 @@ func last[T any](slice []T) (T, bool) {
 }
 
 func newAssertionJob(r *Recorder, a *Assertion) *assertionJob {
+	return newRoundJob(r, r.currentRound, a)
+}
+
+func newRoundJob(r *Recorder, rd *round, a *Assertion) *assertionJob {
 	job := &assertionJob{
-		rec:          r,
-		a:            a,
-		writeCh:      make(chan any, 512),
-		done:         false,
-		currentIndex: 0,
+		rec:   r,
+		round: rd,
+		a:     a,
+		done:  false,
 	}
-	job.index = r.currentRound.addJob(job)
+	job.index = rd.addJob(job)
 	return job
 }
 
-func (j *assertionJob) incPassed() {
-	j.currentIndex++
+// Adds to threads the ones starting at pc (following splits and jumps), returns true if
+// the end of the program is reached.
+func (j *assertionJob) spawn(pc int, threads []*thread, explained []int) ([]*thread, bool) {
+	return j.spawnFrom(pc, threads, explained, map[int]bool{})
+}
+
+func (j *assertionJob) spawnFrom(pc int, threads []*thread, explained []int, visited map[int]bool) ([]*thread, bool) {
+	if visited[pc] {
+		return threads, false
+	}
+	visited[pc] = true
+	in := j.prog[pc]
+	switch in.op {
+	case opMatch:
+		return threads, true
+	case opJump:
+		return j.spawnFrom(in.x, threads, explained, visited)
+	case opSplit:
+		threads, matchedX := j.spawnFrom(in.x, threads, explained, visited)
+		threads, matchedY := j.spawnFrom(in.y, threads, explained, visited)
+		return threads, matchedX || matchedY
+	}
+	t := &thread{pc: pc, c: activate(in.c), start: len(j.writes), explained: explained}
+	if w, ok := in.c.(*within); ok {
+		t.deadline = j.round.clock.Now().Add(w.d)
+	}
+	return append(threads, t), false
+}
+
+// Removes threads that would behave the same as a previous one
+func dedupe(threads []*thread) []*thread {
+	if len(threads) < 2 {
+		return threads
+	}
+	seen := make(map[int]bool)
+	var kept []*thread
+	for _, t := range threads {
+		_, stateful := t.c.(statefulCondition)
+		if stateful || !t.deadline.IsZero() {
+			kept = append(kept, t)
+			continue
+		}
+		if !seen[t.pc] {
+			seen[t.pc] = true
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// Index of the assertion condition reached at pc, used to count passed conditions
+func (j *assertionJob) progress(pc int) int {
+	for j.prog[pc].op == opJump {
+		pc = j.prog[pc].x
+	}
+	return j.prog[pc].step
+}
+
+// Keeps the most advanced failure (or the latest one on equality)
+func (j *assertionJob) fail(t *thread, err, on string) {
+	if step := j.progress(t.pc); step >= j.failure.step {
+		j.failure = failure{step, err + j.stepInfo(t.pc), on}
+	}
 }
 
-func (j *assertionJob) allPassed() bool {
-	return len(j.a.conditions) == j.currentIndex
+// Explains which step of the assertion failed, when it is not obvious
+func (j *assertionJob) stepInfo(pc int) string {
+	in := j.prog[pc]
+	if in.label == "" && len(j.a.conditions) < 2 {
+		return ""
+	}
+	info := fmt.Sprintf("\n\tStep: condition #%v/%v", in.step+1, len(j.a.conditions))
+	if in.label != "" {
+		info += " (" + in.label + ")"
+	}
+	return info
 }
 
-func (j *assertionJob) currentCondition() Condition {
-	return j.a.conditions[j.currentIndex]
+func (j *assertionJob) addError(err string, on string) {
+	output := j.output(err, on)
+	if j.group != nil { // reported by the group, see RecorderGroup
+		j.errors = append(j.errors, output)
+		return
+	}
+	j.rec.addError(output)
 }
 
-func (j *assertionJob) addError(err string, end bool) {
+// describes an error, after the history of the messages received
+func (j *assertionJob) output(err string, on string) string {
 	// introduction
-	numMessages := len(j.writes)
-	messagesLabel := fmt.Sprintf("%v messages received:", numMessages)
+	numMessages := len(j.total)
+	messagesLabel := fmt.Sprintf("%v messages received", numMessages)
 	if numMessages == 0 {
 		messagesLabel = "no message received"
 	} else if numMessages == 1 {
-		messagesLabel = "1 message received:"
+		messagesLabel = "1 message received"
+	}
+	if j.a.filter != nil {
+		messagesLabel += fmt.Sprintf(" (%v after filter)", len(j.writes))
 	}
-	output := fmt.Sprintf("\nIn recorder#%v → assertion#%v, ", j.rec.index, j.index) + messagesLabel + "\n"
-	for _, item := range j.writes {
-		output = fmt.Sprintf("%v\t%#v\n", output, item)
+	if numMessages > 0 {
+		messagesLabel += ":"
+	}
+	output := fmt.Sprintf("\nIn %v → %v#%v, ", j.rec.name, j.round.label(), j.index) + messagesLabel + "\n"
+	since := j.round.since
+	filtered := 0
+	for i, r := range j.total {
+		output = fmt.Sprintf("%v\t[%v] %v", output, relativeTime(r.Time, since), j.rec.format(r.Message))
+		if filtered < len(j.writes) && j.position(filtered) == i {
+			filtered++
+		} else {
+			output += " (filtered out)"
+		}
+		output += "\n"
 	}
 	// actual error
-	errorLabel := "Error occured on write:\n\t"
-	if end {
-		errorLabel = "Error occured on end:\n\t"
+	return output + "Error occured on " + on + ":\n\t" + err + "\n"
+}
+
+// Tries condition c on the current history
+func (j *assertionJob) try(c Condition, end bool) (done, passed bool, err string) {
+	h := history{j.writes, j.records, j.rec.getSends}
+	return h.try(c, end)
+}
+
+// Updates threads after a step, returns true if the job is finished
+func (j *assertionJob) advance(threads []*thread, matched bool) (finished bool) {
+	if matched {
+		if j.a.mode == consistentlyMode {
+			j.held = true
+			j.threads = nil
+			return false
+		}
+		j.done = true
+		j.passed = true
+		return true
 	}
-	output = output + errorLabel + err + "\n"
-	j.rec.addError(output)
+	j.threads = dedupe(threads)
+	if len(j.threads) > 0 {
+		return false
+	}
+	if j.a.mode == eventuallyMode { // retries from next message
+		j.threads, _ = j.spawn(0, nil, nil)
+		return false
+	}
+	j.done = true
+	j.addError(j.failure.err, j.failure.on)
+	return true
 }
 
-func (j *assertionJob) assertOnEnd() {
-	latest, _ := last(j.writes)
-	// on end, done is considered true anyway
-	_, currentPassed, currentErr := j.currentCondition().Try(true, latest, j.writes)
+func (j *assertionJob) start() (finished bool) {
+	j.failure = failure{step: -1}
+	j.prog = compile(j.a.conditions)
+	return j.advance(j.spawn(0, nil, nil))
+}
+
+// Tries each thread on the latest message
+func (j *assertionJob) onWrite() (finished bool) {
+	var next []*thread
+	matched := false
+	for _, t := range j.threads {
+		done, passed, err := j.try(t.c, false)
+		if !done {
+			next = append(next, t)
+		} else if passed {
+			explained := j.explain(t, false)
+			var m bool
+			if next, m = j.spawn(t.pc+1, next, explained); m {
+				matched, j.explained = true, explained
+			}
+		} else {
+			j.fail(t, err, "write")
+		}
+	}
+	return j.advance(next, matched)
+}
+
+// Tries threads whose time window is over as if the end was reached
+func (j *assertionJob) onWindowEnd() (finished bool) {
+	var next []*thread
+	matched := false
+	for _, t := range j.threads {
+		if t.deadline.IsZero() || t.deadline.After(j.windowAt) {
+			next = append(next, t)
+			continue
+		}
+		_, passed, err := j.try(t.c, true)
+		if passed {
+			explained := j.explain(t, true)
+			var m bool
+			if next, m = j.spawn(t.pc+1, next, explained); m {
+				matched, j.explained = true, explained
+			}
+		} else {
+			j.fail(t, err+t.c.(*within).reason(), "window end")
+		}
+	}
+	return j.advance(next, matched)
+}
+
+// Returns the earliest time window end of threads (zero if there is none)
+func (j *assertionJob) threadsDeadline() (earliest time.Time) {
+	for _, t := range j.threads {
+		if !t.deadline.IsZero() && (earliest.IsZero() || t.deadline.Before(earliest)) {
+			earliest = t.deadline
+		}
+	}
+	return
+}
+
+// Returns the time of the next event that does not depend on messages
+func (j *assertionJob) deadline() time.Time {
+	earliest := j.timeoutAt
+	if !j.windowEndAt.IsZero() && j.windowEndAt.Before(earliest) {
+		earliest = j.windowEndAt
+	}
+	if d := j.threadsDeadline(); !d.IsZero() && d.Before(earliest) {
+		earliest = d
+	}
+	return earliest
+}
+
+// Tries remaining threads as if the end was reached, reason explains the end when it's a time window
+func (j *assertionJob) finish(on, reason string) {
 	j.done = true
+	if j.held {
+		j.passed = true
+		return
+	}
+	best := failure{step: -1}
+	bestPassed := false
+	for _, t := range j.threads {
+		// on end, done is considered true anyway
+		_, passed, err := j.try(t.c, true)
+		if passed {
+			explained := j.explain(t, true)
+			if _, matched := j.spawn(t.pc+1, nil, explained); matched {
+				j.explained = explained
+				j.passed = true
+				return
+			}
+			if step := j.progress(t.pc + 1); step >= best.step {
+				best, bestPassed = failure{step, "", on}, true
+			}
+		} else if step := j.progress(t.pc); step >= best.step {
+			best, bestPassed = failure{step, err + j.stepInfo(t.pc), on}, false
+		}
+	}
 
-	if currentPassed {
-		j.incPassed()
+	if j.a.mode == eventuallyMode {
+		j.addError(eventuallyError(bestPassed, best.err)+reason, on)
+		return
+	}
+	if !bestPassed {
+		j.addError(best.err+reason, on)
+	}
+	if on == "end" {
+		j.addError(fmt.Sprintf("only %v/%v condition(s) passed", best.step, len(j.a.conditions)), on)
+	}
+}
+
+func eventuallyError(passed bool, err string) string {
+	output := "[Eventually] condition not satisfied"
+	if !passed && err != "" {
+		output += "\n\t" + err
+	}
+	return output
+}
+
+// Starts the job at the beginning of the round, returns true if it is already finished
+func (j *assertionJob) begin(timeoutAt time.Time) (finished bool) {
+	j.timeoutAt = timeoutAt
+	if j.a.window > 0 {
+		j.windowEndAt = j.round.clock.Now().Add(j.a.window)
+	}
+	j.event = "start"
+	return j.start()
+}
+
+// Processes the latest message of the round log, returns true if the job is finished
+func (j *assertionJob) onMessage(log *messageLog) (finished bool) {
+	n := len(log.records)
+	j.total = log.records[:n]
+	if j.a.usesLog() {
+		j.writes, j.records = log.messages[:n], log.records[:n]
 	} else {
-		j.addError(currentErr, true)
-	}
-	if !j.allPassed() {
-		j.addError(fmt.Sprintf("only %v/%v condition(s) passed", j.currentIndex, len(j.a.conditions)), true)
-	}
-}
-
-// Deals with messages forwarded by recorder, send them to condition and manage condition progress,
-// also dealing with ending logic.
-func (j *assertionJob) loopWithTimeout(timeout time.Duration) {
-	// we found that using time.Sleep is more accurate (= less delay in addition to the specified timeout)
-	// than using <-time.After directly on a for-select case
-	timeoutCh := make(chan string, 1)
-	go func() {
-		time.Sleep(timeout)
-		timeoutCh <- "timeout"
-	}()
-
-	for {
-		select {
-		case w := <-j.writeCh:
-			j.writes = append(j.writes, w)
-
-			currentDone, currentPassed, currentError := j.currentCondition().Try(false, w, j.writes)
-			if currentDone {
-				if currentPassed { // current passed
-					j.incPassed()
-					if j.allPassed() { // all passed
-						j.done = true
-						return
-					}
-				} else {
-					j.done = true
-					j.addError(currentError, false)
-					return
-				}
+		r := log.records[n-1]
+		if j.a.decode != nil {
+			m, err := j.a.decode(r.Message)
+			if err != nil {
+				j.event = "write"
+				j.done = true
+				j.addError(err.Error(), "write")
+				return true
 			}
-		case <-j.rec.doneCh: // conn is closed
-			j.assertOnEnd()
-			return
-		case <-timeoutCh: // timeout is reached
-			j.assertOnEnd()
-			return
+			r = Record{m, r.Time}
+		}
+		if j.a.filter != nil && !j.a.filter(r.Message) {
+			return false
 		}
+		j.writes = append(j.writes, r.Message)
+		j.records = append(j.records, r)
+		j.indexes = append(j.indexes, n-1)
+	}
+	if j.held {
+		return false
+	}
+	j.event = "write"
+	return j.onWrite()
+}
+
+// Processes deadlines reached at the given time, returns true if the job is finished
+func (j *assertionJob) onTimer(at time.Time) (finished bool) {
+	if !j.windowEndAt.IsZero() && !j.windowEndAt.After(at) { // time window of the assertion is over
+		j.event = "window end"
+		j.finish("window end", windowReason(j.a.window))
+		return true
+	}
+	if d := j.threadsDeadline(); !d.IsZero() && !d.After(at) { // time window of some conditions is over
+		j.event = "window end"
+		j.windowAt = at
+		if j.onWindowEnd() {
+			return true
+		}
+	}
+	if !j.timeoutAt.After(at) { // timeout is reached
+		j.event = "end"
+		j.finish("end", "")
+		return true
+	}
+	return false
+}
+
+// Returns the position in the round of the message at index i in the job history
+func (j *assertionJob) position(i int) int {
+	if j.a.usesLog() {
+		return i
 	}
+	return j.indexes[i]
 }
@@ -0,0 +1,89 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssertionJobOutput(t *testing.T) {
+	t.Run("shows total and filtered histories", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		go func() {
+			conn.WriteJSON("chat")
+			conn.WriteJSON("heartbeat")
+		}()
+		rec.NewAssertion().Filter(Not(eq("heartbeat"))).NextToBe("chat").NextToBe("bye")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) == 0 {
+			t.Fatal("assertion should fail")
+		}
+		err := rec.errors[0]
+		if !strings.Contains(err, "2 messages received (1 after filter):") {
+			t.Errorf("output should count filtered messages, got: %v", err)
+		}
+		if !strings.Contains(err, `"heartbeat" (filtered out)`) || strings.Contains(err, `"chat" (filtered out)`) {
+			t.Errorf("output should mark filtered out messages, got: %v", err)
+		}
+	})
+
+	t.Run("does not mention filters when there is none", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		go conn.WriteJSON("chat")
+		rec.NewAssertion().NextToBe("bye")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) == 0 || !strings.Contains(rec.errors[0], "1 message received:\n") || strings.Contains(rec.errors[0], "filter") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+}
+
+func TestAssertionJobFinish(t *testing.T) {
+	cases := []struct {
+		name   string
+		add    func(rec *Recorder)
+		passes bool
+	}{
+		{"default mode passes when last message matches", func(rec *Recorder) {
+			rec.NewAssertion().LastToBe("b")
+		}, true},
+		{"default mode fails when last message differs", func(rec *Recorder) {
+			rec.NewAssertion().LastToBe("a")
+		}, false},
+		{"consistently passes when last message matches on window end", func(rec *Recorder) {
+			rec.Consistently(LastToBe("b")).For(20 * time.Millisecond)
+		}, true},
+		{"consistently fails when last message differs on window end", func(rec *Recorder) {
+			rec.Consistently(LastToBe("a")).For(20 * time.Millisecond)
+		}, false},
+		{"eventually passes when last message matches on window end", func(rec *Recorder) {
+			rec.Eventually(LastToBe("b")).Within(20 * time.Millisecond)
+		}, true},
+		{"eventually fails when last message differs on window end", func(rec *Recorder) {
+			rec.Eventually(LastToBe("a")).Within(20 * time.Millisecond)
+		}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mockT := &testing.T{}
+			conn, rec := NewGorillaMockAndRecorder(mockT)
+
+			go func() {
+				conn.WriteJSON("a")
+				conn.WriteJSON("b")
+			}()
+			c.add(rec)
+			rec.RunAssertions(50 * time.Millisecond)
+
+			if passed := len(rec.errors) == 0; passed != c.passes {
+				t.Errorf("expected passed to be %v, errors: %v", c.passes, rec.errors)
+			}
+		})
+	}
+}
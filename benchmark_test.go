@@ -0,0 +1,58 @@
+package wsmock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// many assertions on a single recorder, that all need the whole history
+func BenchmarkManyAssertions(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%v assertions", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mockT := &testing.T{}
+				conn, rec := NewGorillaMockAndRecorder(mockT)
+				for k := 0; k < n; k++ {
+					rec.NewAssertion().OneToBe("message#99").NextToBe("end")
+				}
+				go func() {
+					for m := 0; m < 100; m++ {
+						conn.WriteJSON(fmt.Sprintf("message#%v", m))
+					}
+					conn.WriteJSON("end")
+				}()
+				rec.RunAssertions(time.Second)
+				if mockT.Failed() {
+					b.Fatal("assertions should succeed")
+				}
+				unindexRecorders(mockT)
+			}
+		})
+	}
+}
+
+// many recorders with a single assertion each, like in a pool of clients
+func BenchmarkManyRecorders(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%v recorders", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mockT := &testing.T{}
+				pool := NewGorillaPool(mockT, n)
+				pool.Group().Each().OneToBe("broadcast")
+				go func() {
+					for _, conn := range pool.Conns() {
+						conn.WriteJSON("broadcast")
+					}
+				}()
+				RunAssertions(mockT, time.Second)
+				if mockT.Failed() {
+					b.Fatal("assertions should succeed")
+				}
+				unindexRecorders(mockT)
+			}
+		})
+	}
+}
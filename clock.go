@@ -0,0 +1,182 @@
+package wsmock
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A Clock tells the time and notifies when durations have elapsed. wsmock uses it for round timeouts,
+// condition time windows, message timestamps and read deadlines.
+//
+// By default wsmock relies on the real time, but a test can use a FakeClock (see UseClock) to make
+// timeouts deterministic and fast.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Sets the Clock used by the mocks and recorders created afterwards with t (and by t-wide RunAssertions).
+//
+// UseClock should be called before NewGorillaMockAndRecorder. When t is over, auto-advancing (see FakeClock.AutoAdvance)
+// is stopped.
+func UseClock(t *testing.T, c Clock) {
+	t.Helper()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.clocks[t]; !ok { // do it once
+		t.Cleanup(func() {
+			unindexClock(t)
+		})
+	}
+	store.clocks[t] = c
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// A FakeClock is a Clock whose time only moves forward when told so: either explicitly with Advance, or
+// automatically when wsmock and the code under test are idle (see AutoAdvance).
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	// auto advance
+	activity int
+	stopCh   chan struct{}
+}
+
+// Returns a FakeClock starting at the current real time, so that deadlines computed with time.Now()
+// in the code under test remain consistent with it.
+func NewFakeClock() *FakeClock {
+	return &FakeClock{now: time.Now()}
+}
+
+// Returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.activity++
+	return c.now
+}
+
+// Returns a channel that receives the fake time once it has been advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.activity++
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	// keeps timers sorted
+	at := c.now.Add(d)
+	i := sort.Search(len(c.timers), func(i int) bool {
+		return c.timers[i].at.After(at)
+	})
+	c.timers = append(c.timers, nil)
+	copy(c.timers[i+1:], c.timers[i:])
+	c.timers[i] = &fakeTimer{at, ch}
+	return ch
+}
+
+// must be called with lock held
+func (c *FakeClock) setNow(now time.Time) {
+	if now.After(c.now) {
+		c.now = now
+	}
+	fired := 0
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			break
+		}
+		timer.ch <- c.now
+		fired++
+	}
+	c.timers = c.timers[fired:]
+}
+
+// releases the timer of ch, if it has not fired yet
+func (c *FakeClock) cancel(ch <-chan time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, timer := range c.timers {
+		if timer.ch == ch {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Moves the fake time forward by d, firing due timers
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setNow(c.now.Add(d))
+}
+
+// Starts moving the fake time automatically: whenever no activity has been observed during the real time
+// duration quiet (no message written or sent, no clock usage), the clock is considered idle and is advanced
+// to its next timer. This way a 60s pongWait can be tested in a few milliseconds.
+//
+// It's a heuristic (goroutines of the code under test can't be observed directly), so quiet should be long
+// enough for the code under test to process messages, for instance 1ms.
+func (c *FakeClock) AutoAdvance(quiet time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopCh != nil {
+		return
+	}
+	c.stopCh = make(chan struct{})
+	go c.autoAdvance(quiet, c.stopCh)
+}
+
+func (c *FakeClock) autoAdvance(quiet time.Duration, stopCh chan struct{}) {
+	ticker := time.NewTicker(quiet)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if c.activity == 0 && len(c.timers) > 0 {
+				c.setNow(c.timers[0].at)
+			}
+			c.activity = 0
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Stops moving the fake time automatically
+func (c *FakeClock) StopAutoAdvance() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopCh != nil {
+		close(c.stopCh)
+		c.stopCh = nil
+	}
+}
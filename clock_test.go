@@ -0,0 +1,72 @@
+package wsmock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Run("Advance fires due timers in order", func(t *testing.T) {
+		c := NewFakeClock()
+		start := c.Now()
+		late := c.After(2 * time.Second)
+		early := c.After(1 * time.Second)
+
+		c.Advance(500 * time.Millisecond)
+		select {
+		case <-early:
+			t.Error("timer should not fire before its time")
+		default:
+		}
+
+		c.Advance(1 * time.Second)
+		select {
+		case now := <-early:
+			if now.Sub(start) != 1500*time.Millisecond {
+				t.Errorf("timer received wrong time: %v", now.Sub(start))
+			}
+		default:
+			t.Error("timer should fire")
+		}
+		select {
+		case <-late:
+			t.Error("late timer should not fire before its time")
+		default:
+		}
+	})
+
+	t.Run("After fires immediately for non positive durations", func(t *testing.T) {
+		c := NewFakeClock()
+		select {
+		case <-c.After(0):
+		default:
+			t.Error("timer should fire")
+		}
+	})
+
+	t.Run("AutoAdvance moves to next timer when idle", func(t *testing.T) {
+		c := NewFakeClock()
+		c.AutoAdvance(time.Millisecond)
+		defer c.StopAutoAdvance()
+
+		select {
+		case <-c.After(time.Hour):
+		case <-time.After(time.Second):
+			t.Error("timer should fire when clock is idle")
+		}
+	})
+}
+
+func TestUseClock(t *testing.T) {
+	t.Run("recorders use the clock set on their test", func(t *testing.T) {
+		mockT := &testing.T{}
+		c := NewFakeClock()
+		UseClock(mockT, c)
+		defer unindexClock(mockT)
+
+		_, rec := NewGorillaMockAndRecorder(mockT)
+		if rec.clock != c {
+			t.Error("recorder should use the fake clock")
+		}
+	})
+}
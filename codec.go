@@ -0,0 +1,132 @@
+package wsmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// A Codec encodes and decodes the data messages of a conn (see GorillaConn.UseCodec)
+type Codec interface {
+	// WebSocket type of the encoded messages (websocket.TextMessage or websocket.BinaryMessage)
+	MessageType() int
+	Marshal(v any) ([]byte, error)
+	// Decodes data into the value pointed to by v (a pointer to an interface receives a generic value)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON text messages (other codecs are provided by the codec/msgpack, codec/cbor and codec/protobuf packages)
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) MessageType() int {
+	return websocket.TextMessage
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+var (
+	textEncodersMu sync.RWMutex
+	textEncoders   []func(msg any) ([]byte, bool)
+)
+
+// returns the text used by string-based predicates (see OneToContain and OneToMatch): strings are used as is,
+// messages handled by a registered text encoder (see RegisterTextEncoder) are encoded with it, and other values
+// are JSON-marshalled
+func textOf(msg any) ([]byte, bool) {
+	if s, ok := msg.(string); ok {
+		return []byte(s), true
+	}
+	textEncodersMu.RLock()
+	encoders := textEncoders
+	textEncodersMu.RUnlock()
+	for _, f := range encoders {
+		if b, ok := f(msg); ok {
+			return b, true
+		}
+	}
+	b, err := json.Marshal(msg)
+	return b, err == nil
+}
+
+// returns the JSON data of a message when decoding it (see As, Protocol and JSONKey): JSON text (string) and JSON
+// binary ([]byte) messages are used as is, and other Go values are JSON-marshalled
+func jsonData(m any) ([]byte, error) {
+	switch msg := m.(type) {
+	case string:
+		return []byte(msg), nil
+	case []byte:
+		return msg, nil
+	default:
+		return json.Marshal(m)
+	}
+}
+
+func (r *Recorder) getCodec() Codec {
+	if r.parent != nil { // sub-recorders share the codec of their conn
+		return r.parent.getCodec()
+	}
+	if c := r.codec.Load(); c != nil {
+		return *c
+	}
+	return nil
+}
+
+// decodes data messages with the codec of the conn if any (and if they have the codec message type),
+// decoding errors being reported as failures
+func (r *Recorder) decodeFrame(m any) any {
+	c := r.getCodec()
+	if c == nil {
+		return m
+	}
+	var data []byte
+	switch v := m.(type) {
+	case []byte:
+		if c.MessageType() != websocket.BinaryMessage {
+			return m
+		}
+		data = v
+	case string:
+		if c.MessageType() != websocket.TextMessage {
+			return m
+		}
+		data = []byte(v)
+	default:
+		return m
+	}
+	var decoded any
+	if err := c.Unmarshal(data, &decoded); err != nil {
+		r.addError(fmt.Sprintf("\nIn %v → [Codec] message can't be decoded: %v\nFailing message (of type %T): %#v\n", r.name, err, m, m))
+		return m
+	}
+	return decoded
+}
+
+// API
+
+// Registers f to get the text of the messages it handles (f returns false for other messages), for messages whose
+// JSON encoding is not meaningful. The text is used by string-based conditions (like OneToContain) and snapshots.
+//
+// Codec packages register their text encoder when imported, for instance codec/protobuf uses protojson.
+func RegisterTextEncoder(f func(msg any) (text []byte, ok bool)) {
+	textEncodersMu.Lock()
+	defer textEncodersMu.Unlock()
+
+	textEncoders = append(textEncoders, f)
+}
+
+// Sets the Codec of the conn: it encodes the messages given to Send (when they are not []byte or string)
+// for ReadMessage and NextReader, and decodes the messages written with WriteMessage (and sent as []byte or
+// string) before they are recorded, if they have the Codec message type.
+func (conn *GorillaConn) UseCodec(c Codec) {
+	conn.recorder.codec.Store(&c)
+}
@@ -0,0 +1,37 @@
+// Package cbor provides a wsmock.Codec for CBOR binary messages.
+package cbor
+
+import (
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gorilla/websocket"
+	"github.com/silently/wsmock"
+)
+
+// CBOR binary messages, decoded into Go values like JSON (maps being map[string]any)
+var Codec wsmock.Codec = newCodec()
+
+type codec struct {
+	dec cbor.DecMode
+}
+
+func newCodec() codec {
+	dec, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]any(nil))}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return codec{dec}
+}
+
+func (codec) MessageType() int {
+	return websocket.BinaryMessage
+}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+func (c codec) Unmarshal(data []byte, v any) error {
+	return c.dec.Unmarshal(data, v)
+}
@@ -0,0 +1,25 @@
+package cbor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCodec(t *testing.T) {
+	if Codec.MessageType() != websocket.BinaryMessage {
+		t.Error("CBOR messages should be binary")
+	}
+	data, err := Codec.Marshal(map[string]any{"kind": "chat", "payload": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v any
+	if err := Codec.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"kind": "chat", "payload": "hi"}; !reflect.DeepEqual(v, want) {
+		t.Errorf("got %#v", v)
+	}
+}
@@ -0,0 +1,25 @@
+// Package msgpack provides a wsmock.Codec for MessagePack binary messages.
+package msgpack
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/silently/wsmock"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePack binary messages, decoded into Go values like JSON (maps being map[string]any)
+var Codec wsmock.Codec = codec{}
+
+type codec struct{}
+
+func (codec) MessageType() int {
+	return websocket.BinaryMessage
+}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
@@ -0,0 +1,65 @@
+// Package protobuf provides a wsmock.Codec for protobuf binary messages.
+//
+// Importing it also makes string-based conditions (like OneToContain) and snapshots use the protojson encoding
+// of protobuf messages (see wsmock.RegisterTextEncoder).
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+	"github.com/silently/wsmock"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	wsmock.RegisterTextEncoder(text)
+}
+
+func text(msg any) ([]byte, bool) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil, false
+	}
+	b, err := protojson.Marshal(m)
+	return b, err == nil
+}
+
+type codec struct {
+	newMessage func() proto.Message
+}
+
+// Returns a Codec for protobuf binary messages. Messages are decoded into the proto.Message returned by
+// newMessage, unless a specific proto.Message is given to Unmarshal.
+func NewCodec(newMessage func() proto.Message) wsmock.Codec {
+	return codec{newMessage}
+}
+
+func (codec) MessageType() int {
+	return websocket.BinaryMessage
+}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("[protobuf.Codec] value (of type %T) is not a proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (c codec) Unmarshal(data []byte, v any) error {
+	switch target := v.(type) {
+	case proto.Message:
+		return proto.Unmarshal(data, target)
+	case *any:
+		m := c.newMessage()
+		if err := proto.Unmarshal(data, m); err != nil {
+			return err
+		}
+		*target = m
+		return nil
+	}
+	return errors.New("[protobuf.Codec] Unmarshal argument should be a proto.Message or a pointer to an interface")
+}
@@ -0,0 +1,39 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodec(t *testing.T) {
+	c := NewCodec(func() proto.Message { return &wrapperspb.StringValue{} })
+	if c.MessageType() != websocket.BinaryMessage {
+		t.Error("protobuf messages should be binary")
+	}
+	data, err := c.Marshal(wrapperspb.String("hi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v any
+	if err := c.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := v.(*wrapperspb.StringValue); !ok || m.Value != "hi" {
+		t.Errorf("got %#v", v)
+	}
+	if _, err := c.Marshal("hi"); err == nil {
+		t.Error("Marshal should fail on values that are not proto.Message")
+	}
+}
+
+func TestText(t *testing.T) {
+	if b, ok := text(wrapperspb.String("hi")); !ok || string(b) != `"hi"` {
+		t.Errorf("text should use protojson, got %q", b)
+	}
+	if _, ok := text("hi"); ok {
+		t.Error("text should only handle proto messages")
+	}
+}
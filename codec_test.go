@@ -0,0 +1,71 @@
+package wsmock
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestJSONCodec(t *testing.T) {
+	if JSONCodec.MessageType() != websocket.TextMessage {
+		t.Error("JSON messages should be text")
+	}
+	data, err := JSONCodec.Marshal(map[string]any{"kind": "chat", "payload": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v any
+	if err := JSONCodec.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"kind": "chat", "payload": "hi"}; !reflect.DeepEqual(v, want) {
+		t.Errorf("got %#v", v)
+	}
+}
+
+type textMessage struct {
+	text string
+}
+
+func TestTextOf(t *testing.T) {
+	RegisterTextEncoder(func(msg any) ([]byte, bool) {
+		if m, ok := msg.(textMessage); ok {
+			return []byte(m.text), true
+		}
+		return nil, false
+	})
+
+	cases := []struct {
+		msg  any
+		want string
+	}{
+		{"raw", "raw"},
+		{map[string]any{"a": 1}, `{"a":1}`},
+		{textMessage{"registered"}, "registered"},
+	}
+	for _, c := range cases {
+		if b, ok := textOf(c.msg); !ok || string(b) != c.want {
+			t.Errorf("textOf(%#v) = %q", c.msg, b)
+		}
+	}
+}
+
+func TestJSONData(t *testing.T) {
+	cases := []struct {
+		msg  any
+		want string
+	}{
+		{`{"kind":"join"}`, `{"kind":"join"}`},
+		{[]byte(`{"kind":"join"}`), `{"kind":"join"}`},
+		{map[string]any{"kind": "join"}, `{"kind":"join"}`},
+	}
+	for _, c := range cases {
+		if b, err := jsonData(c.msg); err != nil || string(b) != c.want {
+			t.Errorf("jsonData(%#v) = %q, %v", c.msg, b, err)
+		}
+	}
+	if _, err := jsonData(complex64(1)); err == nil {
+		t.Error("jsonData should fail on values that can't be JSON-marshalled")
+	}
+}
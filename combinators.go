@@ -0,0 +1,172 @@
+package wsmock
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Combinators are Conditions built upon other Conditions, that are run concurrently on the same messages.
+//
+// Time windows (see Assertion.Within) set on combined Conditions are ignored: set them on the combinator instead.
+
+// indents the (possibly multiline) error of a combined Condition
+func nestedError(index int, err string) string {
+	return fmt.Sprintf("\n\t#%v %v", index, strings.ReplaceAll(err, "\n", "\n\t"))
+}
+
+type combined struct {
+	cs []Condition
+	// state
+	instances []Condition
+	done      []bool
+	errs      []string
+}
+
+func newCombined(cs []Condition) combined {
+	instances := make([]Condition, len(cs))
+	for i, c := range cs {
+		instances[i] = activate(c)
+	}
+	return combined{cs, instances, make([]bool, len(cs)), make([]string, len(cs))}
+}
+
+// tries all Conditions that are not done yet, returns the index of the first one that passed (or -1)
+// and the number of failed ones
+func (c *combined) tryAll(end bool, h *history) (passedIndex, failed int) {
+	passedIndex = -1
+	for i, instance := range c.instances {
+		if c.done[i] {
+			if c.errs[i] != "" {
+				failed++
+			}
+			continue
+		}
+		done, passed, err := h.try(instance, end)
+		if done || end {
+			c.done[i] = true
+			if passed {
+				if passedIndex == -1 {
+					passedIndex = i
+				}
+			} else {
+				c.errs[i] = err
+				if c.errs[i] == "" {
+					c.errs[i] = "condition failed"
+				}
+				failed++
+			}
+		}
+	}
+	return
+}
+
+// The anyCondition struct implements Condition. It succeeds as soon as one of its Conditions passes
+// and fails when all of them failed.
+type anyCondition struct {
+	combined
+}
+
+// Returns a condition that succeeds as soon as one of the given conditions passes (the first one to be done and
+// passed wins), and fails when all of them failed (explaining why each one failed)
+func AnyCondition(cs ...Condition) Condition {
+	return &anyCondition{newCombined(cs)}
+}
+
+func (c *anyCondition) fresh() Condition {
+	return AnyCondition(c.cs...)
+}
+
+func (c *anyCondition) Try(end bool, latest any, _ []any) (done, passed bool, err string) {
+	return c.tryTimed(end, historyOf(latest))
+}
+
+func (c *anyCondition) tryTimed(end bool, h *history) (done, passed bool, err string) {
+	passedIndex, failed := c.tryAll(end, h)
+	if passedIndex != -1 { // succeeds
+		return true, true, ""
+	}
+	if failed == len(c.cs) || end { // fails
+		err = "[AnyCondition] none of the conditions passed:"
+		for i, e := range c.errs {
+			err += nestedError(i+1, e)
+		}
+		return true, false, err
+	}
+	// unfinished
+	return false, false, ""
+}
+
+// The allConditions struct implements Condition. It succeeds when all of its Conditions passed
+// and fails as soon as one of them fails.
+type allConditions struct {
+	combined
+}
+
+// Returns a condition that succeeds when all of the given conditions passed (they are run concurrently on the
+// same messages), and fails as soon as one of them fails
+func AllConditions(cs ...Condition) Condition {
+	return &allConditions{newCombined(cs)}
+}
+
+func (c *allConditions) fresh() Condition {
+	return AllConditions(c.cs...)
+}
+
+func (c *allConditions) Try(end bool, latest any, _ []any) (done, passed bool, err string) {
+	return c.tryTimed(end, historyOf(latest))
+}
+
+func (c *allConditions) tryTimed(end bool, h *history) (done, passed bool, err string) {
+	_, failed := c.tryAll(end, h)
+	if failed > 0 { // fails
+		err = "[AllConditions] some conditions failed:"
+		for i, e := range c.errs {
+			if e != "" {
+				err += nestedError(i+1, e)
+			}
+		}
+		return true, false, err
+	}
+	for _, done := range c.done {
+		if !done { // unfinished
+			return false, false, ""
+		}
+	}
+	// succeeds
+	return true, true, ""
+}
+
+// The notCondition struct implements Condition. It is done when its Condition is done, with the opposite outcome.
+type notCondition struct {
+	c        Condition
+	instance Condition
+}
+
+// Returns a condition that is done when the given one is done, and succeeds if it failed (and vice versa)
+func NotCondition(c Condition) Condition {
+	return &notCondition{c, activate(c)}
+}
+
+func (c *notCondition) fresh() Condition {
+	return NotCondition(c.c)
+}
+
+func (c *notCondition) Try(end bool, latest any, _ []any) (done, passed bool, err string) {
+	return c.tryTimed(end, historyOf(latest))
+}
+
+func (c *notCondition) tryTimed(end bool, h *history) (done, passed bool, err string) {
+	done, passed, _ = h.try(c.instance, end)
+	if !done && !end { // unfinished
+		return false, false, ""
+	}
+	if passed {
+		latest, ok := h.latest()
+		err = "[NotCondition] condition unexpectedly passed"
+		if ok && !end {
+			err += fmt.Sprintf("\n\tFailing message (of type %T): %+v", latest.Message, latest.Message)
+		}
+		return true, false, err
+	}
+	return true, true, ""
+}
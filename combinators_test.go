@@ -0,0 +1,37 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	t.Run("AnyCondition explains why each condition failed", func(t *testing.T) {
+		c := AnyCondition(OneToBe("a"), NextToBe("b"))
+		h := &history{sends: func() []Record { return nil }}
+		for _, msg := range []any{"z", "y"} {
+			h.writes = append(h.writes, msg)
+			h.records = append(h.records, Record{Message: msg})
+			if done, _, _ := h.try(c, false); done {
+				t.Errorf("AnyCondition should not be done after %#v", msg)
+			}
+		}
+		done, passed, err := h.try(c, true)
+		if !done || passed {
+			t.Error("AnyCondition should fail on end")
+		}
+		if !strings.Contains(err, "#1 [OneToBe]") || !strings.Contains(err, "#2 [NextToBe]") {
+			t.Errorf("AnyCondition should explain both failures, got: %v", err)
+		}
+	})
+
+	t.Run("combinators are fresh when activated", func(t *testing.T) {
+		c := AllConditions(OneToBe("a"), OneToBe("b"))
+		if done, _, _ := historyOf("a").try(c, false); done {
+			t.Error("AllConditions should not be done")
+		}
+		if done, _, _ := historyOf("b").try(activate(c), false); done {
+			t.Error("fresh AllConditions should not be done")
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package wsmock
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Condition constructors, also used by the chainable methods of Assertion. They make it possible to use
+// built-in conditions as values, for instance with Recorder.Eventually.
+
+// Time windows
+
+// Returns a condition that succeeds if no message is received during d, and fails as soon as one is
+func NoneWithin(d time.Duration) Condition {
+	never := func(any) bool { return false }
+	return newWithin(newAllTo(never, fmt.Sprintf("[NoneWithin] message received within: %v", d)), d)
+}
+
+// OneTo*
+
+// Returns a condition that succeeds if a new message is equal to the given interface (according to the equality operator `==`)
+func OneToBe(target any) Condition {
+	return oneToBe(target, eq(target))
+}
+
+// like OneToBe, equality being decided by f (see TypedAssertion)
+func oneToBe(target any, f Predicate) Condition {
+	return newOneTo(f, fmt.Sprintf("[OneToBe] no message is equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if a new message checks the Predicate
+func OneToCheck(f Predicate) Condition {
+	return newOneTo(f, fmt.Sprintf("[OneToCheck] no message checks predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if a new message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
+func OneToContain(sub string) Condition {
+	return newOneTo(contain(sub), fmt.Sprintf("[OneToContain] no message contains string: %v", sub))
+}
+
+// Returns a condition that succeeds if a new message matches the regular expression
+func OneToMatch(re *regexp.Regexp) Condition {
+	return newOneTo(match(re), fmt.Sprintf("[OneToMatch] no message matches regexp: %v", re))
+}
+
+// OneNot*
+
+// Returns a condition that succeeds if a new message is not equal to the given interface (according to the equality operator `==`)
+func OneNotToBe(target any) Condition {
+	return oneNotToBe(target, eq(target))
+}
+
+// like OneNotToBe, equality being decided by f (see TypedAssertion)
+func oneNotToBe(target any, f Predicate) Condition {
+	return newOneTo(Not(f), fmt.Sprintf("[OneNotToBe] message unexpectedly equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if a new message does not check the Predicate
+func OneNotToCheck(f Predicate) Condition {
+	return newOneTo(Not(f), fmt.Sprintf("[OneNotToCheck] message unexpectedly checks predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if a new message does not contain the given string (messages that can't be converted to strings are JSON-marshalled first)
+func OneNotToContain(sub string) Condition {
+	return newOneTo(Not(contain(sub)), fmt.Sprintf("[OneNotToContain] message unexpectedly contains string: %v", sub))
+}
+
+// Returns a condition that succeeds if a new message does not match the regular expression
+func OneNotToMatch(re *regexp.Regexp) Condition {
+	return newOneTo(Not(match(re)), fmt.Sprintf("[OneNotToMatch] message unexpectedly matches regexp: %v", re))
+}
+
+// NextTo*
+
+// Returns a condition that succeeds if the next message is equal to the given interface (according to the equality operator `==`)
+func NextToBe(target any) Condition {
+	return nextToBe(target, eq(target))
+}
+
+// like NextToBe, equality being decided by f (see TypedAssertion)
+func nextToBe(target any, f Predicate) Condition {
+	return newNextTo(f, fmt.Sprintf("[NextToBe] next message is not equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if the next message checks the Predicate
+func NextToCheck(f Predicate) Condition {
+	return newNextTo(f, fmt.Sprintf("[NextToCheck] next message does not check predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if the next message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
+func NextToContain(sub string) Condition {
+	return newNextTo(contain(sub), fmt.Sprintf("[NextToContain] next message does not contain string: %v", sub))
+}
+
+// Returns a condition that succeeds if the next message matches the regular expression
+func NextToMatch(re *regexp.Regexp) Condition {
+	return newNextTo(match(re), fmt.Sprintf("[NextToMatch] next message does not match regexp: %v", re))
+}
+
+// NextNot*
+
+// Returns a condition that succeeds if the next message is not equal to the given interface (according to the equality operator `==`)
+func NextNotToBe(target any) Condition {
+	return nextNotToBe(target, eq(target))
+}
+
+// like NextNotToBe, equality being decided by f (see TypedAssertion)
+func nextNotToBe(target any, f Predicate) Condition {
+	return newNextTo(Not(f), fmt.Sprintf("[NextNotToBe] next message unexpectedly equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if the next message does not check the Predicate
+func NextNotToCheck(f Predicate) Condition {
+	return newNextTo(Not(f), fmt.Sprintf("[NextNotToCheck] next message unexpectedly checks predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if the next message does not contain the given string (messages that can't be converted to strings are JSON-marshalled first)
+func NextNotToContain(sub string) Condition {
+	return newNextTo(Not(contain(sub)), fmt.Sprintf("[NextNotToContain] next message unexpectedly contains string: %v", sub))
+}
+
+// Returns a condition that succeeds if the next message does not match the regular expression
+func NextNotToMatch(re *regexp.Regexp) Condition {
+	return newNextTo(Not(match(re)), fmt.Sprintf("[NextNotToMatch] next message unexpectedly matches regexp: %v", re))
+}
+
+// Unordered
+
+// Returns a condition that succeeds once each of the given interfaces is equal to a distinct new message, in any order (according to the equality operator `==`)
+func SetToBe(targets ...any) Condition {
+	return setToBe(targets, eq)
+}
+
+// like SetToBe, equality being decided by the predicates returned by eq (see TypedAssertion)
+func setToBe(targets []any, eq func(target any) Predicate) Condition {
+	labels := make([]string, len(targets))
+	fs := make([]Predicate, len(targets))
+	for i, target := range targets {
+		labels[i] = fmt.Sprintf("%#v", target)
+		fs[i] = eq(target)
+	}
+	return newSetTo(fs, labels, "[SetToBe] messages are not equal to the expected set")
+}
+
+// Returns a condition that succeeds once each of the given Predicates is checked by a distinct new message, in any order
+func AllOf(fs ...Predicate) Condition {
+	labels := make([]string, len(fs))
+	for i, f := range fs {
+		labels[i] = getFunctionName(f)
+	}
+	return newSetTo(fs, labels, "[AllOf] messages do not check the expected set of predicates")
+}
+
+// Timing
+
+// Returns a condition that succeeds if a new message checking replyF is written at most max after the latest message
+// sent to the conn (with GorillaConn.Send) checking sentF
+func LatencyFrom(sentF, replyF Predicate, max time.Duration) Condition {
+	return newLatencyFrom(sentF, replyF, max, fmt.Sprintf("[LatencyFrom] no reply checking %v within %v after a sent message checking %v", getFunctionName(replyF), max, getFunctionName(sentF)))
+}
+
+// Returns a condition that succeeds if the time intervals between consecutive remaining messages checking the Predicate are between min and max
+func IntervalBetween(f Predicate, min, max time.Duration) Condition {
+	return newIntervalBetween(f, min, max, fmt.Sprintf("[IntervalBetween] interval between messages checking %v is not between %v and %v", getFunctionName(f), min, max))
+}
+
+// Last*
+
+// Returns a condition that succeeds if the last message is equal to the given interface (according to the equality operator `==`)
+func LastToBe(target any) Condition {
+	return lastToBe(target, eq(target))
+}
+
+// like LastToBe, equality being decided by f (see TypedAssertion)
+func lastToBe(target any, f Predicate) Condition {
+	return newLastTo(f, fmt.Sprintf("[LastToBe] last message is not equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if the last message checks the Predicate
+func LastToCheck(f Predicate) Condition {
+	return newLastTo(f, fmt.Sprintf("[LastToCheck] last message deos not check predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if the last message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
+func LastToContain(sub string) Condition {
+	return newLastTo(contain(sub), fmt.Sprintf("[LastToContain] last message does not contain string: %v", sub))
+}
+
+// Returns a condition that succeeds if the last message matches the regular expression
+func LastToMatch(re *regexp.Regexp) Condition {
+	return newLastTo(match(re), fmt.Sprintf("[LastToMatch] last message does not match regexp: %v", re))
+}
+
+// LastNot*
+
+// Returns a condition that succeeds if the last message is not equal to the given interface (according to the equality operator `==`)
+func LastNotToBe(target any) Condition {
+	return lastNotToBe(target, eq(target))
+}
+
+// like LastNotToBe, equality being decided by f (see TypedAssertion)
+func lastNotToBe(target any, f Predicate) Condition {
+	return newLastTo(Not(f), fmt.Sprintf("[LastNotToBe] last message unexpectedly equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if the last message does not check the Predicate
+func LastNotToCheck(f Predicate) Condition {
+	return newLastTo(Not(f), fmt.Sprintf("[LastNotToCheck] last message unexpectedly checks predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if the last message does not contain the given string (messages that can't be converted to strings are JSON-marshalled first)
+func LastNotToContain(sub string) Condition {
+	return newLastTo(Not(contain(sub)), fmt.Sprintf("[LastNotToContain] last message unexpectedly contains string: %v", sub))
+}
+
+// Returns a condition that succeeds if the last message does not match the regular expression
+func LastNotToMatch(re *regexp.Regexp) Condition {
+	return newLastTo(Not(match(re)), fmt.Sprintf("[LastNotToMatch] last message unexpectedly matches regexp: %v", re))
+}
+
+// All*
+
+// Returns a condition that succeeds if all remaining messages are equal to the given interface (according to the equality operator `==`)
+func AllToBe(target any) Condition {
+	return allToBe(target, eq(target))
+}
+
+// like AllToBe, equality being decided by f (see TypedAssertion)
+func allToBe(target any, f Predicate) Condition {
+	return newAllTo(f, fmt.Sprintf("[AllToBe] message is not equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if all remaining messages check the Predicate
+func AllToCheck(f Predicate) Condition {
+	return newAllTo(f, fmt.Sprintf("[AllToCheck] message does not check predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if all remaining messages contain the given string (messages that can't be converted to strings are JSON-marshalled first)
+func AllToContain(sub string) Condition {
+	return newAllTo(contain(sub), fmt.Sprintf("[AllToContain] message does not contain string: %v", sub))
+}
+
+// Returns a condition that succeeds if all remaining messages match the regular expression
+func AllToMatch(re *regexp.Regexp) Condition {
+	return newAllTo(match(re), fmt.Sprintf("[AllToMatch] message does not match regexp: %v", re))
+}
+
+// None*
+
+// Returns a condition that succeeds if no remaining message is equal to the given interface (according to the equality operator `==`)
+func NoneToBe(target any) Condition {
+	return noneToBe(target, eq(target))
+}
+
+// like NoneToBe, equality being decided by f (see TypedAssertion)
+func noneToBe(target any, f Predicate) Condition {
+	return newNoneTo(f, fmt.Sprintf("[NoneToBe] message unexpectedly equal to: %#v", target))
+}
+
+// Returns a condition that succeeds if no remaining message checks the Predicate
+func NoneToCheck(f Predicate) Condition {
+	return newNoneTo(f, fmt.Sprintf("[NoneToCheck] message unexpectedly checks predicate: %v", getFunctionName(f)))
+}
+
+// Returns a condition that succeeds if no remaining message contains the given string (messages that can't be converted to strings are JSON-marshalled first)
+func NoneToContain(sub string) Condition {
+	return newNoneTo(contain(sub), fmt.Sprintf("[NoneToContain] message unexpectedly contains string: %v", sub))
+}
+
+// Returns a condition that succeeds if no remaining message matches the regular expression
+func NoneToMatch(re *regexp.Regexp) Condition {
+	return newNoneTo(match(re), fmt.Sprintf("[NoneToMatch] message unexpectedly matches regexp: %v", re))
+}
@@ -1,6 +1,9 @@
 package wsmock
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Generic interface that is chained to form Assertions. The Try method of a Condition is called in two cases:
 //
@@ -32,6 +35,20 @@ func (f ConditionFunc) Try(end bool, latest any, all []any) (done, passed bool,
 	return f(end, latest, all)
 }
 
+// Implemented by conditions holding state: a fresh copy is used each time the condition becomes active,
+// so that the same condition value can be used several times.
+type statefulCondition interface {
+	fresh() Condition
+}
+
+// returns the instance of c to be used when it becomes active
+func activate(c Condition) Condition {
+	if sc, ok := c.(statefulCondition); ok {
+		return sc.fresh()
+	}
+	return c
+}
+
 // The oneTo struct implements Condition. Its Predicate function is called on each message and on end.
 //
 // If the Predicate returns true, asserting is done and succeeds,
@@ -124,3 +141,229 @@ func (c allTo) Try(end bool, latest any, _ []any) (done, passed bool, err string
 		}
 	}
 }
+
+// The noneTo struct is an allTo whose Predicate is negated: messages are checked the same way, but they are not
+// explained by the condition in strict mode (see Recorder.Strict).
+type noneTo struct {
+	*allTo
+}
+
+func newNoneTo(f Predicate, err string) *noneTo {
+	return &noneTo{newAllTo(Not(f), err)}
+}
+
+// The setTo struct implements Condition. Each of its Predicates has to be checked by a distinct message,
+// in any order. Messages are assigned to Predicates with a maximum bipartite matching, so that a message
+// checking several Predicates does not prevent the others from being satisfied.
+//
+// If all Predicates are matched, asserting is done and succeeds,
+// If the end is reached, asserting is done and fails (listing unmatched Predicates and leftover messages).
+type setTo struct {
+	fs     []Predicate
+	labels []string // describe each Predicate in errors
+	err    string
+	// state
+	messages []any
+	edges    [][]int // message index -> indexes of the Predicates it checks
+	matchOf  []int   // Predicate index -> matched message index (-1 if unmatched)
+	matched  int
+}
+
+func newSetTo(fs []Predicate, labels []string, err string) *setTo {
+	matchOf := make([]int, len(fs))
+	for i := range matchOf {
+		matchOf[i] = -1
+	}
+	return &setTo{fs: fs, labels: labels, err: err, matchOf: matchOf}
+}
+
+func (c *setTo) fresh() Condition {
+	return newSetTo(c.fs, c.labels, c.err)
+}
+
+// tries to find an augmenting path starting from message m (Kuhn's algorithm)
+func (c *setTo) augment(m int, visited []bool) bool {
+	for _, p := range c.edges[m] {
+		if visited[p] {
+			continue
+		}
+		visited[p] = true
+		if c.matchOf[p] == -1 || c.augment(c.matchOf[p], visited) {
+			c.matchOf[p] = m
+			return true
+		}
+	}
+	return false
+}
+
+func (c *setTo) report() string {
+	isMatched := make([]bool, len(c.messages))
+	output := fmt.Sprintf("%v\n\tExpectation(s) not satisfied (%v/%v):", c.err, len(c.fs)-c.matched, len(c.fs))
+	for p, m := range c.matchOf {
+		if m == -1 {
+			output += fmt.Sprintf("\n\t\t%v", c.labels[p])
+		} else {
+			isMatched[m] = true
+		}
+	}
+	output += "\n\tLeftover message(s):"
+	leftover := 0
+	for m, msg := range c.messages {
+		if !isMatched[m] {
+			leftover++
+			output += fmt.Sprintf("\n\t\t%#v", msg)
+		}
+	}
+	if leftover == 0 {
+		output += " none"
+	}
+	return output
+}
+
+func (c *setTo) Try(end bool, latest any, _ []any) (done, passed bool, err string) {
+	if c.matched == len(c.fs) {
+		return true, true, ""
+	}
+	if end {
+		return true, false, c.report()
+	}
+	var edges []int
+	for p, f := range c.fs {
+		if f(latest) {
+			edges = append(edges, p)
+		}
+	}
+	c.messages = append(c.messages, latest)
+	c.edges = append(c.edges, edges)
+	if c.augment(len(c.messages)-1, make([]bool, len(c.fs))) {
+		c.matched++
+	}
+	if c.matched == len(c.fs) { // succeeds
+		return true, true, ""
+	}
+	// unfinished
+	return false, false, ""
+}
+
+// The within struct decorates a Condition with a time window that starts when the Condition becomes
+// the current one of its Assertion. When the window is over, the Condition is tried as if the end was reached.
+type within struct {
+	Condition
+	d time.Duration
+}
+
+func newWithin(c Condition, d time.Duration) *within {
+	if w, ok := c.(*within); ok { // replaces previous window
+		c = w.Condition
+	}
+	return &within{c, d}
+}
+
+func (c *within) fresh() Condition {
+	return &within{activate(c.Condition), c.d}
+}
+
+// explains failures occuring when window is over
+func (c *within) reason() string {
+	return windowReason(c.d)
+}
+
+func windowReason(d time.Duration) string {
+	return fmt.Sprintf("\n\tReason: not done within %v", d)
+}
+
+// Implemented by conditions that need timestamps: the job then calls tryTimed instead of Try,
+// with a history giving records of written messages and of messages sent to the conn during the round.
+//
+// Their Try method is only a fallback that timestamps messages when it is called.
+type timedCondition interface {
+	tryTimed(end bool, h *history) (done, passed bool, err string)
+}
+
+// The intervalBetween struct implements Condition. Its Predicate function is called on each message,
+// and the time interval between two consecutive matching messages has to be between min and max.
+//
+// If an interval is out of bounds, asserting is done and fails,
+// If the end is reached, asserting is done and succeeds.
+type intervalBetween struct {
+	f        Predicate
+	min, max time.Duration
+	err      string
+	// state
+	previous *Record
+}
+
+func newIntervalBetween(f Predicate, min, max time.Duration, err string) *intervalBetween {
+	return &intervalBetween{f: f, min: min, max: max, err: err}
+}
+
+func (c *intervalBetween) fresh() Condition {
+	return newIntervalBetween(c.f, c.min, c.max, c.err)
+}
+
+func (c *intervalBetween) Try(end bool, latest any, _ []any) (done, passed bool, err string) {
+	return c.tryTimed(end, historyOf(latest))
+}
+
+func (c *intervalBetween) tryTimed(end bool, h *history) (done, passed bool, err string) {
+	if end {
+		return true, true, ""
+	}
+	latest, _ := h.latest()
+	if !c.f(latest.Message) {
+		return false, false, "" // ongoing
+	}
+	if c.previous != nil {
+		interval := latest.Time.Sub(c.previous.Time)
+		if interval < c.min || interval > c.max {
+			return true, false, c.err + fmt.Sprintf("\n\tInterval: %v between messages %#v and %#v", interval, c.previous.Message, latest.Message) // failed
+		}
+	}
+	c.previous = &latest
+	return false, false, "" // ongoing
+}
+
+// The latencyFrom struct implements Condition. Its reply Predicate is called on each message and,
+// when true, the latency is measured from the latest sent message (with GorillaConn.Send) checking the sent Predicate.
+//
+// If the latency is at most max, asserting is done and succeeds,
+// If the latency is greater than max, asserting is done and fails,
+// If the end is reached, asserting is done and fails.
+type latencyFrom struct {
+	sentF, replyF Predicate
+	max           time.Duration
+	err           string
+}
+
+func newLatencyFrom(sentF, replyF Predicate, max time.Duration, err string) *latencyFrom {
+	return &latencyFrom{sentF, replyF, max, err}
+}
+
+func (c *latencyFrom) Try(end bool, latest any, _ []any) (done, passed bool, err string) {
+	return c.tryTimed(end, historyOf(latest))
+}
+
+func (c *latencyFrom) tryTimed(end bool, h *history) (done, passed bool, err string) {
+	// fails on end
+	if end {
+		return true, false, c.err + "\n\tReason: no reply received"
+	}
+	latest, _ := h.latest()
+	if !c.replyF(latest.Message) {
+		return false, false, "" // unfinished
+	}
+	sends := h.sends()
+	for i := len(sends) - 1; i >= 0; i-- {
+		sent := sends[i]
+		if sent.Time.After(latest.Time) || !c.sentF(sent.Message) {
+			continue
+		}
+		latency := latest.Time.Sub(sent.Time)
+		if latency <= c.max { // succeeds
+			return true, true, ""
+		}
+		return true, false, c.err + fmt.Sprintf("\n\tLatency: %v between sent message %#v and reply %#v", latency, sent.Message, latest.Message)
+	}
+	// reply to no matching sent message, unfinished
+	return false, false, ""
+}
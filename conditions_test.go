@@ -0,0 +1,94 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetToReport(t *testing.T) {
+	t.Run("setTo lists unmatched expectations and leftover messages on end", func(t *testing.T) {
+		c := newSetTo([]Predicate{eq("a"), eq("b"), eq("c")}, []string{`"a"`, `"b"`, `"c"`}, "[SetToBe] error")
+
+		for _, msg := range []any{"z", "b", "a"} {
+			if done, _, _ := c.Try(false, msg, nil); done {
+				t.Errorf("setTo should not be done after %#v", msg)
+			}
+		}
+		done, passed, err := c.Try(true, nil, nil)
+		if !done || passed {
+			t.Error("setTo should be done and failed on end")
+		}
+		if !strings.Contains(err, "Expectation(s) not satisfied (1/3):\n\t\t\"c\"") {
+			t.Errorf("setTo should report unmatched expectation, got: %v", err)
+		}
+		if !strings.Contains(err, "Leftover message(s):\n\t\t\"z\"") {
+			t.Errorf("setTo should report leftover message, got: %v", err)
+		}
+	})
+
+	t.Run("setTo reassigns messages to reach a complete matching", func(t *testing.T) {
+		c := newSetTo([]Predicate{contain("a"), contain("b")}, []string{"a", "b"}, "[AllOf] error")
+
+		if done, _, _ := c.Try(false, "ab", nil); done {
+			t.Error("setTo should not be done after first message")
+		}
+		if done, passed, _ := c.Try(false, "a", nil); !done || !passed {
+			t.Error("setTo should succeed after second message")
+		}
+	})
+}
+
+func TestWithin(t *testing.T) {
+	t.Run("within explains window failures", func(t *testing.T) {
+		c := newWithin(newOneTo(eq("a"), "[OneToBe] error"), 10*time.Millisecond)
+		if !strings.Contains(c.reason(), "not done within 10ms") {
+			t.Errorf("within should explain window failure, got: %v", c.reason())
+		}
+	})
+
+	t.Run("within replaces previous window", func(t *testing.T) {
+		c := newWithin(newWithin(newOneTo(eq("a"), ""), time.Second), time.Millisecond)
+		if _, ok := c.Condition.(*within); ok || c.d != time.Millisecond {
+			t.Error("within should not be nested")
+		}
+	})
+}
+
+func TestTimedConditions(t *testing.T) {
+	start := time.Now()
+	at := func(ms int, msg any) Record {
+		return Record{msg, start.Add(time.Duration(ms) * time.Millisecond)}
+	}
+	// history with given latest record and sends
+	hist := func(latest Record, sends []Record) *history {
+		return &history{[]any{latest.Message}, []Record{latest}, func() []Record { return sends }}
+	}
+
+	t.Run("intervalBetween checks consecutive matching messages", func(t *testing.T) {
+		c := newIntervalBetween(eq("tick"), 10*time.Millisecond, 20*time.Millisecond, "[IntervalBetween] error")
+		for _, r := range []Record{at(0, "tick"), at(5, "other"), at(15, "tick"), at(30, "tick")} {
+			if done, _, _ := c.tryTimed(false, hist(r, nil)); done {
+				t.Errorf("intervalBetween should not be done after %#v", r.Message)
+			}
+		}
+		if done, passed, err := c.tryTimed(false, hist(at(35, "tick"), nil)); !done || passed || !strings.Contains(err, "Interval: 5ms") {
+			t.Errorf("intervalBetween should fail on short interval, got: %v", err)
+		}
+	})
+
+	t.Run("latencyFrom measures latency from latest matching sent message", func(t *testing.T) {
+		c := newLatencyFrom(eq("ping"), eq("pong"), 10*time.Millisecond, "[LatencyFrom] error")
+		sends := []Record{at(0, "ping"), at(20, "ping"), at(25, "other")}
+
+		if done, _, _ := c.tryTimed(false, hist(at(22, "other"), sends)); done {
+			t.Error("latencyFrom should skip non replies")
+		}
+		if done, passed, _ := c.tryTimed(false, hist(at(28, "pong"), sends)); !done || !passed {
+			t.Error("latencyFrom should succeed")
+		}
+		if done, passed, err := c.tryTimed(false, hist(at(40, "pong"), sends)); !done || passed || !strings.Contains(err, "Latency: 20ms") {
+			t.Errorf("latencyFrom should fail on late reply, got: %v", err)
+		}
+	})
+}
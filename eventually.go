@@ -0,0 +1,53 @@
+package wsmock
+
+import "time"
+
+// An EventuallyAssertion succeeds as soon as its condition passes, and fails if it has not passed
+// when its time window is over (see Within). Failing attempts are retried, starting from the next message.
+//
+// Conditions that are only done at the end (like LastToBe or NoneToBe) are tried when the time window is over,
+// and the assertion succeeds if they pass then.
+type EventuallyAssertion struct {
+	a *Assertion
+}
+
+// A ConsistentlyAssertion fails as soon as its condition fails, and otherwise waits for the end of its
+// time window (see For) to succeed.
+type ConsistentlyAssertion struct {
+	a *Assertion
+}
+
+// Initializes an assertion that succeeds as soon as c passes (the RunAssertions timeout is used as a time window,
+// unless a tighter one is set with Within).
+//
+// For instance rec.Eventually(wsmock.OneToBe("ready")).Within(50 * time.Millisecond) succeeds as soon as "ready" is
+// received, but fails if it's not received within 50ms.
+func (r *Recorder) Eventually(c Condition) *EventuallyAssertion {
+	a := &Assertion{mode: eventuallyMode}
+	a.append(c)
+	newAssertionJob(r, a)
+	return &EventuallyAssertion{a}
+}
+
+// Initializes an assertion that requires c to hold during the whole time window (the RunAssertions timeout is used
+// as a time window, unless a tighter one is set with For). The assertion does not succeed before the window is over,
+// even if c passed.
+//
+// For instance rec.Consistently(wsmock.NoneToBe("error")).For(50 * time.Millisecond) fails as soon as "error" is
+// received, but otherwise succeeds after 50ms.
+func (r *Recorder) Consistently(c Condition) *ConsistentlyAssertion {
+	a := &Assertion{mode: consistentlyMode}
+	a.append(c)
+	newAssertionJob(r, a)
+	return &ConsistentlyAssertion{a}
+}
+
+// Sets the time window: the assertion fails if its condition has not passed within d after the round starts
+func (e *EventuallyAssertion) Within(d time.Duration) {
+	e.a.window = d
+}
+
+// Sets the time window: the condition has to hold during d after the round starts
+func (c *ConsistentlyAssertion) For(d time.Duration) {
+	c.a.window = d
+}
@@ -2,6 +2,15 @@ module github.com/silently/wsmock
 
 go 1.21
 
-require github.com/gorilla/websocket v1.5.1
+require (
+	github.com/fxamacker/cbor/v2 v2.9.0
+	github.com/gorilla/websocket v1.5.1
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/protobuf v1.36.5
+)
 
-require golang.org/x/net v0.19.0 // indirect
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/net v0.19.0 // indirect
+)
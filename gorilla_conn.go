@@ -3,9 +3,12 @@ package wsmock
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
+	"os"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -49,6 +52,30 @@ type GorillaConn struct {
 	recorder     *Recorder
 	closed       bool
 	closedCh     chan struct{}
+	// keepalive simulation
+	mu           sync.Mutex
+	readDeadline time.Time
+	deadline     *deadlineTimer // timer of readDeadline, shared by reads until it changes
+	pongHandler  func(appData string) error
+}
+
+// fires once when a read deadline is exceeded, unless stopped before
+type deadlineTimer struct {
+	clock     Clock
+	timerCh   <-chan time.Time
+	expiredCh chan struct{} // closed when deadline is exceeded
+	stopCh    chan struct{}
+}
+
+// implemented by clocks able to release a timer that won't be waited for (like FakeClock)
+type cancelableClock interface {
+	cancel(ch <-chan time.Time)
+}
+
+// control frames go through serverReadCh along with data messages, but are handled while reading
+type controlFrame struct {
+	messageType int
+	data        string
 }
 
 type gorillaWriteCloser struct {
@@ -102,66 +129,171 @@ func NewGorillaMockAndRecorder(t *testing.T) (*GorillaConn, *Recorder) {
 // Send does not make any asumption on its message argument type (and does not serializes it),
 // this will be decided upon what Read* function is used to retrieve it
 func (conn *GorillaConn) Send(message any) {
+	conn.recorder.recordSend(conn.recorder.sendFrame(message), conn.recorder.decodeFrame(message))
 	conn.serverReadCh <- message
 }
 
+// like Send, but gives up if conn is closed or stopCh is closed, returns false if message was not sent
+// (and then not recorded)
+func (conn *GorillaConn) sendUntil(message any, stopCh <-chan struct{}) bool {
+	select { // checked first since select picks a random ready case
+	case <-conn.closedCh:
+		return false
+	case <-stopCh:
+		return false
+	default:
+	}
+	select {
+	case conn.serverReadCh <- message:
+		conn.recorder.recordSend(conn.recorder.sendFrame(message), conn.recorder.decodeFrame(message))
+		return true
+	case <-conn.closedCh:
+	case <-stopCh:
+	}
+	return false
+}
+
 // Stub API (used by server)
 
 // Close the conn, preventing further reads or writes.
 func (conn *GorillaConn) Close() error {
 	if !conn.closed {
 		conn.closed = true
+		conn.recorder.addEvent(closeEvent, "", Record{nil, conn.recorder.clock.Now()})
 		close(conn.closedCh)
+		conn.mu.Lock()
+		conn.stopDeadline()
+		conn.mu.Unlock()
 		conn.recorder.stop()
 	}
 	return nil
 }
 
-// Parses as JSON the first message available on conn and stores the result in the value pointed to by v
-// While waiting for it, it can return sooner if conn is closed
-func (conn *GorillaConn) ReadJSON(v any) error {
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Pointer || rv.IsNil() {
-		return errors.New("ReadJSON: argument should be a pointer")
-	}
+// Waits for the next data message sent to conn, handling control frames on the way.
+// While waiting for it, it can return sooner if conn is closed or if read deadline is exceeded.
+func (conn *GorillaConn) read() (any, error) {
 	for {
 		select {
 		case read := <-conn.serverReadCh:
-			b, err := json.Marshal(read)
-			if err != nil {
-				return err
+			if frame, ok := read.(controlFrame); ok {
+				conn.handleControl(frame)
+				continue
 			}
-			return json.Unmarshal(b, v)
+			conn.recorder.addEvent(readEvent, conn.recorder.sendFrame(read), Record{read, conn.recorder.clock.Now()})
+			return read, nil
 		case <-conn.closedCh:
-			return errors.New("[wsmock] conn closed while reading")
+			return nil, errors.New("[wsmock] conn closed while reading")
+		case <-conn.readDeadlineCh():
+			return nil, fmt.Errorf("[wsmock] read deadline exceeded: %w", os.ErrDeadlineExceeded)
 		}
 	}
 }
 
+func newDeadlineTimer(clock Clock, at time.Time) *deadlineTimer {
+	d := &deadlineTimer{
+		clock:     clock,
+		timerCh:   clock.After(at.Sub(clock.Now())),
+		expiredCh: make(chan struct{}),
+		stopCh:    make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-d.timerCh:
+			close(d.expiredCh)
+		case <-d.stopCh:
+		}
+	}()
+	return d
+}
+
+func (d *deadlineTimer) stop() {
+	close(d.stopCh)
+	if c, ok := d.clock.(cancelableClock); ok {
+		c.cancel(d.timerCh)
+	}
+}
+
+// returns a channel that is closed when read deadline is exceeded (or nil if there is no deadline)
+func (conn *GorillaConn) readDeadlineCh() <-chan struct{} {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.readDeadline.IsZero() {
+		return nil
+	}
+	if conn.deadline == nil {
+		conn.deadline = newDeadlineTimer(conn.recorder.clock, conn.readDeadline)
+	}
+	return conn.deadline.expiredCh
+}
+
+// must be called with lock held
+func (conn *GorillaConn) stopDeadline() {
+	if conn.deadline != nil {
+		conn.deadline.stop()
+		conn.deadline = nil
+	}
+}
+
+func (conn *GorillaConn) handleControl(frame controlFrame) {
+	conn.recorder.addEvent(readEvent, controlFrameType(frame.messageType), Record{frame.data, conn.recorder.clock.Now()})
+	if frame.messageType == websocket.PongMessage {
+		conn.mu.Lock()
+		h := conn.pongHandler
+		conn.mu.Unlock()
+
+		if h != nil {
+			h(frame.data)
+		}
+	}
+}
+
+// Parses as JSON the first message available on conn and stores the result in the value pointed to by v
+// While waiting for it, it can return sooner if conn is closed or if read deadline is exceeded
+func (conn *GorillaConn) ReadJSON(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("ReadJSON: argument should be a pointer")
+	}
+	read, err := conn.read()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(read)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
 // Returns the first message available on conn, as []byte:
 // - []byte message returned as is
 // - string message converted to [byte]
-// - other message types are JSON marshalled
-// While waiting for a message, it can return sooner if conn is closed
+// - other message types are JSON marshalled (or encoded with the Codec of the conn, see UseCodec)
+// While waiting for a message, it can return sooner if conn is closed or if read deadline is exceeded
 func (conn *GorillaConn) ReadMessage() (messageType int, p []byte, err error) {
-	for {
-		select {
-		case read := <-conn.serverReadCh:
-			switch v := read.(type) {
-			case []byte:
-				return websocket.BinaryMessage, v, nil
-			case string:
-				return websocket.TextMessage, []byte(v), nil
-			default:
-				b, err := json.Marshal(read)
-				if err != nil {
-					return -1, nil, err
-				}
-				return websocket.TextMessage, b, nil
+	read, err := conn.read()
+	if err != nil {
+		return -1, nil, err
+	}
+	switch v := read.(type) {
+	case []byte:
+		return websocket.BinaryMessage, v, nil
+	case string:
+		return websocket.TextMessage, []byte(v), nil
+	default:
+		if c := conn.recorder.getCodec(); c != nil {
+			b, err := c.Marshal(read)
+			if err != nil {
+				return -1, nil, err
 			}
-		case <-conn.closedCh:
-			return -1, nil, errors.New("[wsmock] conn closed while reading")
+			return c.MessageType(), b, nil
 		}
+		b, err := json.Marshal(read)
+		if err != nil {
+			return -1, nil, err
+		}
+		return websocket.TextMessage, b, nil
 	}
 }
 
@@ -182,23 +314,32 @@ func (conn *GorillaConn) WriteJSON(m any) error {
 	if conn.closed {
 		return errors.New("[wsmock] conn closed while writing")
 	}
-	conn.recorder.writeCh <- m
+	conn.recorder.record(m)
 	return nil
 }
 
 // Writes a []byte msg to its recorder, but returns an error if conn is closed.
+//
+// Like with Gorilla, control messages (ping, pong, close) are written with WriteControl.
 func (conn *GorillaConn) WriteMessage(messageType int, data []byte) error {
 	if conn.closed {
 		return errors.New("[wsmock] conn closed while writing")
 	}
+	if isControl(messageType) {
+		return conn.WriteControl(messageType, data, time.Time{})
+	}
 	if messageType == websocket.TextMessage {
-		conn.recorder.writeCh <- string(data)
+		conn.recorder.recordFrame(textFrame, conn.recorder.decodeFrame(string(data)))
 	} else {
-		conn.recorder.writeCh <- data
+		conn.recorder.recordFrame(binaryFrame, conn.recorder.decodeFrame(data))
 	}
 	return nil
 }
 
+func isControl(messageType int) bool {
+	return messageType == websocket.CloseMessage || messageType == websocket.PingMessage || messageType == websocket.PongMessage
+}
+
 // IGorilla noop implementations
 
 // Mock not implemented yet
@@ -223,11 +364,17 @@ func (conn *GorillaConn) PingHandler() func(appData string) error {
 	}
 }
 
-// Mock not implemented yet
+// Returns the handler set with SetPongHandler (or a noop one)
 func (conn *GorillaConn) PongHandler() func(appData string) error {
-	return func(appData string) error {
-		return nil
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.pongHandler == nil {
+		return func(appData string) error {
+			return nil
+		}
 	}
+	return conn.pongHandler
 }
 
 // Mock not implemented yet
@@ -246,11 +393,24 @@ func (conn *GorillaConn) SetCompressionLevel(level int) error {
 // Mock not implemented yet
 func (conn *GorillaConn) SetPingHandler(h func(appData string) error) {}
 
-// Mock not implemented yet
-func (conn *GorillaConn) SetPongHandler(h func(appData string) error) {}
+// Sets the handler called (while reading) when the client answers a ping with a pong
+func (conn *GorillaConn) SetPongHandler(h func(appData string) error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
 
-// Mock not implemented yet
+	conn.pongHandler = h
+}
+
+// Sets the deadline after which reads fail, according to the Clock of the test (see UseClock).
+// A zero value means reads do not time out.
 func (conn *GorillaConn) SetReadDeadline(t time.Time) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if !t.Equal(conn.readDeadline) {
+		conn.stopDeadline()
+		conn.readDeadline = t
+	}
 	return nil
 }
 
@@ -272,8 +432,16 @@ func (conn *GorillaConn) UnderlyingConn() net.Conn {
 	return &net.TCPConn{}
 }
 
-// Mock not implemented yet
+// Simulates a client that answers pings: a ping message makes the client send a pong, which is handled
+// (see SetPongHandler) during a subsequent read. Other control messages are noop.
 func (conn *GorillaConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	if conn.closed {
+		return errors.New("[wsmock] conn closed while writing")
+	}
+	conn.recorder.addEvent(writeEvent, controlFrameType(messageType), Record{string(data), conn.recorder.clock.Now()})
+	if messageType == websocket.PingMessage {
+		conn.serverReadCh <- controlFrame{websocket.PongMessage, string(data)}
+	}
 	return nil
 }
 
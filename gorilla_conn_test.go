@@ -3,9 +3,12 @@ package wsmock
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -24,7 +27,7 @@ func TestGorillaConnWrite(t *testing.T) {
 			t.Error(err)
 		}
 
-		if len(rec.writeCh) != 1 {
+		if len(rec.pending) != 1 {
 			t.Error("recorder should contain one write")
 		}
 	})
@@ -38,7 +41,7 @@ func TestGorillaConnWrite(t *testing.T) {
 			t.Error(err)
 		}
 
-		if len(rec.writeCh) != 1 {
+		if len(rec.pending) != 1 {
 			t.Error("recorder should contain one write")
 		}
 	})
@@ -52,7 +55,7 @@ func TestGorillaConnWrite(t *testing.T) {
 			t.Error(err)
 		}
 
-		if len(rec.writeCh) != 1 {
+		if len(rec.pending) != 1 {
 			t.Error("recorder should contain one write")
 		}
 	})
@@ -198,6 +201,19 @@ func TestGorillaConnRead(t *testing.T) {
 		}
 	})
 
+	t.Run("ReadJSON keeps string messages that are valid JSON", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, _ := NewGorillaMockAndRecorder(mockT)
+
+		for _, sent := range []string{"42", "true", "null"} {
+			conn.Send(sent)
+			var s string
+			if err := conn.ReadJSON(&s); err != nil || s != sent {
+				t.Errorf("ReadJSON should return %q, got %q, %v", sent, s, err)
+			}
+		}
+	})
+
 	t.Run("ReadJSON fails when argument is not a pointer", func(t *testing.T) {
 		mockT := &testing.T{}
 		conn, _ := NewGorillaMockAndRecorder(mockT)
@@ -210,4 +226,55 @@ func TestGorillaConnRead(t *testing.T) {
 			t.Error("ReadJSON fails with non pointer argument")
 		}
 	})
+
+	t.Run("sendUntil does not send nor record once stopped", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		stopCh := make(chan struct{})
+		if !conn.sendUntil("first", stopCh) {
+			t.Error("sendUntil should send before stop")
+		}
+		close(stopCh)
+		if conn.sendUntil("second", stopCh) {
+			t.Error("sendUntil should not send after stop")
+		}
+		if n := len(conn.serverReadCh); n != 1 {
+			t.Errorf("expected 1 message sent, got %v", n)
+		}
+		if ts := rec.Transcript(); len(ts) != 1 || ts[0].Message != "first" {
+			t.Errorf("unexpected transcript: %+v", ts)
+		}
+	})
+
+	t.Run("reads share the timer of their deadline", func(t *testing.T) {
+		mockT := &testing.T{}
+		clock := NewFakeClock()
+		UseClock(mockT, clock)
+		defer unindexClock(mockT)
+		conn, _ := NewGorillaMockAndRecorder(mockT)
+
+		conn.SetReadDeadline(clock.Now().Add(time.Minute))
+		for i := 0; i < 3; i++ {
+			conn.Send("ping")
+			conn.ReadMessage()
+		}
+		if len(clock.timers) != 1 {
+			t.Errorf("expected 1 timer, got %v", len(clock.timers))
+		}
+
+		conn.SetReadDeadline(clock.Now().Add(time.Second))
+		conn.Send("ping")
+		conn.ReadMessage()
+		if len(clock.timers) != 1 || !clock.timers[0].at.Equal(clock.Now().Add(time.Second)) {
+			t.Error("stale deadline timer should be released")
+		}
+
+		clock.Advance(time.Second)
+		for i := 0; i < 2; i++ {
+			if _, _, err := conn.ReadMessage(); !errors.Is(err, os.ErrDeadlineExceeded) {
+				t.Errorf("ReadMessage should fail with exceeded deadline, got %v", err)
+			}
+		}
+	})
 }
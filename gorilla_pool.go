@@ -0,0 +1,117 @@
+package wsmock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// A GorillaPool holds many GorillaConn mocks and their recorders, to simulate many clients in the same test.
+type GorillaPool struct {
+	conns  []*GorillaConn
+	recs   []*Recorder
+	byName map[string]int
+}
+
+type poolConfig struct {
+	name    func(i int) string
+	handler func(conn IGorilla)
+}
+
+// A PoolOption configures NewGorillaPool
+type PoolOption func(c *poolConfig)
+
+// Names the conns of the pool (names are used in logs and by GorillaPool.Named)
+func WithNames(name func(i int) string) PoolOption {
+	return func(c *poolConfig) {
+		c.name = name
+	}
+}
+
+// Runs the server handler in a new goroutine for each conn of the pool
+func WithHandler(handler func(conn IGorilla)) PoolOption {
+	return func(c *poolConfig) {
+		c.handler = handler
+	}
+}
+
+// Returns a pool of n conns and their recorders, indexed from 0 to n-1.
+//
+// Recorders of the pool are regular recorders of t: they are run by wsmock.RunAssertions(t, timeout), and recorders
+// without assertions don't start any goroutine when run.
+func NewGorillaPool(t *testing.T, n int, opts ...PoolOption) *GorillaPool {
+	c := &poolConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	p := &GorillaPool{byName: make(map[string]int)}
+	for i := 0; i < n; i++ {
+		conn, rec := NewGorillaMockAndRecorder(t)
+		if c.name != nil {
+			name := c.name(i)
+			p.byName[name] = i
+			rec.name = fmt.Sprintf("%v (%v)", rec.name, name)
+		}
+		p.conns = append(p.conns, conn)
+		p.recs = append(p.recs, rec)
+	}
+	if c.handler != nil {
+		for _, conn := range p.conns {
+			go c.handler(conn)
+		}
+	}
+	return p
+}
+
+// Returns the number of conns in the pool
+func (p *GorillaPool) Len() int {
+	return len(p.conns)
+}
+
+// Returns the conn and recorder at index i
+func (p *GorillaPool) Get(i int) (*GorillaConn, *Recorder) {
+	return p.conns[i], p.recs[i]
+}
+
+// Returns the conn and recorder with the given name (see WithNames), or nils if there is none
+func (p *GorillaPool) Named(name string) (*GorillaConn, *Recorder) {
+	i, ok := p.byName[name]
+	if !ok {
+		return nil, nil
+	}
+	return p.Get(i)
+}
+
+// Returns the conns of the pool
+func (p *GorillaPool) Conns() []*GorillaConn {
+	return append([]*GorillaConn(nil), p.conns...)
+}
+
+// Returns the recorders of the pool
+func (p *GorillaPool) Recorders() []*Recorder {
+	return append([]*Recorder(nil), p.recs...)
+}
+
+// Returns a RecorderGroup made of all the recorders of the pool
+func (p *GorillaPool) Group() *RecorderGroup {
+	return NewRecorderGroup(p.Recorders()...)
+}
+
+// Sends the message to all conns of the pool (see GorillaConn.Send)
+func (p *GorillaPool) SendAll(message any) {
+	for _, conn := range p.conns {
+		conn.Send(message)
+	}
+}
+
+// Closes all conns of the pool
+func (p *GorillaPool) CloseAll() {
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
+
+// Runs the assertions of all the recorders of the pool (see Recorder.RunAssertions)
+func (p *GorillaPool) RunAssertions(timeout time.Duration) {
+	p.Group().RunAssertions(timeout)
+}
@@ -0,0 +1,37 @@
+package wsmock
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGorillaPool(t *testing.T) {
+	t.Run("names recorders", func(t *testing.T) {
+		pool := NewGorillaPool(&testing.T{}, 3, WithNames(func(i int) string { return fmt.Sprintf("user%v", i) }))
+		conn, rec := pool.Named("user1")
+		if conn == nil || rec != pool.Recorders()[1] || !strings.HasSuffix(rec.name, "(user1)") {
+			t.Errorf("unexpected named recorder: %v", rec.name)
+		}
+		if conn, rec := pool.Named("unknown"); conn != nil || rec != nil {
+			t.Error("Named should return nils for unknown names")
+		}
+	})
+
+	t.Run("does not leave goroutines behind for idle recorders", func(t *testing.T) {
+		mockT := &testing.T{}
+		pool := NewGorillaPool(mockT, 1000)
+		_, rec := pool.Get(0)
+		before := runtime.NumGoroutine()
+		for i := 0; i < 3; i++ {
+			rec.NewAssertion().NoneToBe("error")
+			RunAssertions(mockT, time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if after := runtime.NumGoroutine(); after > before+5 {
+			t.Errorf("rounds should not leave goroutines behind: %v before, %v after", before, after)
+		}
+	})
+}
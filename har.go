@@ -0,0 +1,140 @@
+package wsmock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+// HAR structure, restricted to WebSocket entries as exported by browsers
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		URL string `json:"url"`
+	} `json:"request"`
+	Messages []harMessage `json:"_webSocketMessages"`
+}
+
+type harMessage struct {
+	Type   string  `json:"type"`   // "send" or "receive"
+	Time   float64 `json:"time"`   // seconds since epoch
+	Opcode int     `json:"opcode"` // 1 for text, 2 for binary (base64 data)
+	Data   string  `json:"data"`
+}
+
+func harTime(seconds float64) time.Time {
+	sec, frac := math.Modf(seconds)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e6))*1e3).UTC()
+}
+
+// converts a HAR message to a transcript event, text frames being kept as strings and binary frames as base64
+// (like in JSON Lines transcripts), control frames being skipped
+func (m harMessage) event(conn string) (TranscriptEvent, bool) {
+	e := TranscriptEvent{Time: harTime(m.Time), Conn: conn}
+	switch m.Type {
+	case "send":
+		e.Event = sendEvent
+	case "receive":
+		e.Event = writeEvent
+	default:
+		return e, false
+	}
+	switch m.Opcode {
+	case 1:
+		e.Type, e.Message = textFrame, m.Data
+	case 2:
+		e.Type, e.Message = binaryFrame, m.Data
+	default: // control frames are not replayed
+		return e, false
+	}
+	return e, true
+}
+
+// API
+
+// A HARScenario is the WebSocket session of a HAR entry: messages sent by the browser are the client script,
+// and messages it received are the messages expected from the handler.
+type HARScenario struct {
+	URL        string
+	Transcript TranscriptEvents // send and write events on a conn named after URL
+}
+
+// Reads the WebSocket sessions (entries with `_webSocketMessages`) of a HAR file, as exported by browsers
+func ReadHAR(r io.Reader) ([]HARScenario, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("[wsmock] invalid HAR: %w", err)
+	}
+	var scenarios []HARScenario
+	for _, entry := range har.Log.Entries {
+		if entry.Messages == nil {
+			continue
+		}
+		s := HARScenario{URL: entry.Request.URL}
+		for _, m := range entry.Messages {
+			if e, ok := m.event(s.URL); ok {
+				s.Transcript = append(s.Transcript, e)
+			}
+		}
+		scenarios = append(scenarios, s)
+	}
+	if len(scenarios) == 0 {
+		return nil, errors.New("[wsmock] no WebSocket entry in HAR")
+	}
+	return scenarios, nil
+}
+
+// Reads the WebSocket sessions of a HAR file (see ReadHAR)
+func LoadHAR(path string) ([]HARScenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadHAR(f)
+}
+
+// Sends the messages sent by the browser to conn, in order and without delay. WithDecodedJSONText option applies,
+// other ReplayOptions are ignored.
+func (s HARScenario) Script(conn *GorillaConn, opts ...ReplayOption) {
+	c := &replayConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	for _, e := range s.Transcript {
+		if e.Event == sendEvent {
+			conn.Send(c.sentMessage(e))
+		}
+	}
+}
+
+// Adds an assertion to rec expecting the messages received by the browser, in order, and no other message. WithIgnoredFields and
+// WithIgnoredMessages options apply, other ReplayOptions are ignored.
+func (s HARScenario) Expect(rec *Recorder, opts ...ReplayOption) *Assertion {
+	c, maskErr := newReplayConfig(opts)
+	if maskErr != nil {
+		a := rec.NewAssertion()
+		a.WithCondition(ConditionFunc(func(bool, any, []any) (done, passed bool, err string) {
+			return true, false, "[HARScenario] " + maskErr.Error()
+		}))
+		return a
+	}
+	return c.expectWrites(rec, s.Transcript, s.URL)
+}
+
+// Replays the session with handler (see Replay)
+func (s HARScenario) Replay(t *testing.T, handler func(conn IGorilla), opts ...ReplayOption) {
+	t.Helper()
+	Replay(t, handler, s.Transcript, opts...)
+}
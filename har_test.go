@@ -0,0 +1,44 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadHAR(t *testing.T) {
+	t.Run("converts WebSocket messages to transcript events", func(t *testing.T) {
+		har := `{"log":{"entries":[{"request":{"url":"wss://example.com/ws"},"_webSocketMessages":[
+			{"type":"send","time":1704164645.5,"opcode":1,"data":"hello"},
+			{"type":"send","time":1704164645.6,"opcode":1,"data":"{\"kind\":\"join\"}"},
+			{"type":"receive","time":1704164645.7,"opcode":2,"data":"AQI="},
+			{"type":"receive","time":1704164645.8,"opcode":9,"data":""}
+		]}]}}`
+		scenarios, err := ReadHAR(strings.NewReader(har))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts := scenarios[0].Transcript
+		if len(ts) != 3 {
+			t.Fatalf("expected 3 events, got %+v", ts)
+		}
+		if ts[0].Event != sendEvent || ts[0].Type != textFrame || ts[0].Message != "hello" || ts[0].Conn != "wss://example.com/ws" {
+			t.Errorf("unexpected text event: %+v", ts[0])
+		}
+		if ts[1].Type != textFrame || ts[1].Message != `{"kind":"join"}` {
+			t.Errorf("unexpected JSON text event: %+v", ts[1])
+		}
+		if ts[2].Event != writeEvent || ts[2].Type != binaryFrame {
+			t.Errorf("unexpected binary event: %+v", ts[2])
+		}
+		if got := ts[0].Time.UnixMilli(); got != 1704164645500 {
+			t.Errorf("unexpected time: %v", got)
+		}
+	})
+
+	t.Run("fails without WebSocket entries", func(t *testing.T) {
+		_, err := ReadHAR(strings.NewReader(`{"log":{"entries":[{"request":{"url":"https://example.com"}}]}}`))
+		if err == nil {
+			t.Error("ReadHAR should fail")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func containsA(msg any) bool {
+	str, ok := msg.(string)
+	return ok && strings.Contains(str, "a")
+}
+
+func containsB(msg any) bool {
+	str, ok := msg.(string)
+	return ok && strings.Contains(str, "b")
+}
+
+func TestAllOf_Success(t *testing.T) {
+	t.Run("succeeds when each predicate is checked by a distinct message", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("b")
+			conn.WriteJSON("z")
+			conn.WriteJSON("a")
+		}()
+
+		// assert
+		rec.NewAssertion().AllOf(containsA, containsB)
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("AllOf should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when a greedy assignment would fail", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("ab") // checks both predicates
+			conn.WriteJSON("a")  // only checks containsA
+		}()
+
+		// assert
+		rec.NewAssertion().AllOf(containsA, containsB)
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("AllOf should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestAllOf_Failure(t *testing.T) {
+	t.Run("fails when one message checks all predicates", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("ab")
+
+		// assert
+		rec.NewAssertion().AllOf(containsA, containsB)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("AllOf should fail because predicates need distinct messages")
+		}
+	})
+}
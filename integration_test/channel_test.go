@@ -0,0 +1,102 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+type Event struct {
+	Channel string `json:"channel"`
+	Seq     int    `json:"seq"`
+}
+
+func TestChannel_Success(t *testing.T) {
+	t.Run("succeeds when ordering is checked per channel", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Channel(ws.JSONKey("channel"))
+
+		// script
+		go func() {
+			conn.WriteJSON(Event{"news", 1})
+			conn.WriteJSON(Event{"sports", 1})
+			conn.WriteJSON(Event{"news", 2})
+			conn.WriteJSON(Event{"sports", 2})
+		}()
+
+		// assert
+		rec.Sub("news").NewAssertion().NextToBe(Event{"news", 1}).NextToBe(Event{"news", 2})
+		rec.Sub("sports").NewAssertion().NextToBe(Event{"sports", 1}).NextToBe(Event{"sports", 2})
+		rec.NewAssertion().OneToBe(Event{"sports", 2})
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Channel should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with test-level RunAssertions", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Channel(ws.JSONKey("channel"))
+
+		// script
+		go func() {
+			conn.WriteJSON(map[string]any{"channel": "news", "text": "hello"})
+			conn.WriteJSON("no channel")
+		}()
+
+		// assert
+		rec.Sub("news").NewAssertion().NextToContain("hello").NoneToBe("no channel")
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Channel should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestChannel_Failure(t *testing.T) {
+	t.Run("fails when ordering is wrong within a channel", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Channel(ws.JSONKey("channel"))
+
+		// script
+		go func() {
+			conn.WriteJSON(Event{"news", 2})
+			conn.WriteJSON(Event{"sports", 1})
+			conn.WriteJSON(Event{"news", 1})
+		}()
+
+		// assert
+		rec.Sub("news").NewAssertion().NextToBe(Event{"news", 1}).NextToBe(Event{"news", 2})
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Channel should fail because news events are not ordered")
+		}
+	})
+
+	t.Run("fails when a channel receives nothing", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Channel(ws.JSONKey("channel"))
+
+		// script
+		go conn.WriteJSON(Event{"news", 1})
+
+		// assert
+		rec.Sub("weather").NewAssertion().OneToCheck(anything)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Channel should fail because no weather event is received")
+		}
+	})
+}
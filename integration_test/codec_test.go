@@ -0,0 +1,149 @@
+package integration_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	ws "github.com/silently/wsmock"
+	"github.com/silently/wsmock/codec/cbor"
+	wsmsgpack "github.com/silently/wsmock/codec/msgpack"
+	"github.com/silently/wsmock/codec/protobuf"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// replies to each message with the same message written with the codec
+func echoHandler(conn ws.IGorilla) {
+	for {
+		messageType, p, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(messageType, p)
+	}
+}
+
+func TestCodec_Success(t *testing.T) {
+	t.Run("succeeds with MessagePack messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		conn.UseCodec(wsmsgpack.Codec)
+		go echoHandler(conn)
+
+		// script
+		conn.Send(map[string]any{"kind": "chat", "payload": "hello"})
+
+		// assert
+		rec.NewAssertion().OneToCheck(func(m any) bool {
+			msg, ok := m.(map[string]any)
+			return ok && msg["payload"] == "hello"
+		})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToCheck should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with string-based conditions on CBOR messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		conn.UseCodec(cbor.Codec)
+		go echoHandler(conn)
+
+		// script
+		conn.Send(Message{"chat", "hello"})
+
+		// assert
+		rec.NewAssertion().OneToContain(`"payload":"hello"`)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToContain should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with typed assertions on MessagePack binary sends", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		conn.UseCodec(wsmsgpack.Codec)
+		go echoHandler(conn)
+
+		// script
+		data, _ := msgpack.Marshal(Message{"join", "room1"})
+		conn.Send(data)
+
+		// assert
+		ws.As[Message](rec).NewAssertion().OneToBe(Message{"join", "room1"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with string-based conditions on protobuf messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		conn.UseCodec(protobuf.NewCodec(func() proto.Message { return &wrapperspb.StringValue{} }))
+		go echoHandler(conn)
+
+		// script
+		conn.Send(wrapperspb.String("hello"))
+
+		// assert
+		rec.NewAssertion().OneToMatch(regexp.MustCompile(`^"hello"$`))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToMatch should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with protobuf messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		conn.UseCodec(protobuf.NewCodec(func() proto.Message { return &wrapperspb.StringValue{} }))
+		go echoHandler(conn)
+
+		// script
+		conn.Send(wrapperspb.String("hello"))
+
+		// assert
+		rec.NewAssertion().OneToCheck(func(m any) bool {
+			return proto.Equal(m.(proto.Message), wrapperspb.String("hello"))
+		})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToCheck should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestCodec_Failure(t *testing.T) {
+	t.Run("fails when a message can't be decoded", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		conn.UseCodec(wsmsgpack.Codec)
+
+		// script
+		go conn.WriteMessage(websocket.BinaryMessage, []byte{0xc1}) // never used in MessagePack
+
+		// assert
+		rec.NewAssertion().OneToCheck(func(m any) bool { return true })
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("decoding error should be reported")
+		}
+	})
+}
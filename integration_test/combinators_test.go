@@ -0,0 +1,142 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestAnyCondition(t *testing.T) {
+	t.Run("succeeds when one condition passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("error")
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.AnyCondition(ws.OneToBe("ok"), ws.OneToBe("error")))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("AnyCondition should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when all conditions fail", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("other")
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.AnyCondition(ws.NextToBe("ok"), ws.NextToBe("error")))
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("AnyCondition should fail")
+		}
+	})
+}
+
+func TestAllConditions(t *testing.T) {
+	t.Run("succeeds when all conditions pass on the same messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("b")
+			conn.WriteJSON("a")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			WithCondition(ws.AllConditions(ws.OneToBe("a"), ws.OneToBe("b"))).
+			NoneToBe("c")
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("AllConditions should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when one condition fails", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("c")
+		}()
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.AllConditions(ws.OneToBe("a"), ws.NoneToBe("c")))
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("AllConditions should fail")
+		}
+	})
+}
+
+func TestNotCondition(t *testing.T) {
+	t.Run("succeeds when condition fails", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("b")
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.NotCondition(ws.OneToBe("a")))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("NotCondition should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when condition passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("a")
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.NotCondition(ws.OneToBe("a")))
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("NotCondition should fail")
+		}
+	})
+}
+
+func TestPredicateCombinators(t *testing.T) {
+	t.Run("Or, And and Not can be combined", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("ab")
+
+		// assert
+		rec.NewAssertion().OneToCheck(ws.And(containsA, ws.Or(containsB, ws.Not(containsA))))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToCheck should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestConsistently_Success(t *testing.T) {
+	t.Run("succeeds when condition holds during the whole window", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("ok")
+			time.Sleep(1 * durationUnit)
+			conn.WriteJSON("ok")
+		}()
+
+		// assert
+		rec.Consistently(ws.NoneToBe("error")).For(3 * durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Consistently should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed < 3*durationUnit || elapsed > 10*durationUnit {
+				t.Errorf("Consistently should succeed when window is over")
+			}
+		}
+	})
+
+	t.Run("waits for the end of the window even if condition passed", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("ready")
+
+		// assert
+		rec.Consistently(ws.OneToBe("ready")).For(3 * durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Consistently should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed < 3*durationUnit {
+				t.Errorf("Consistently should wait for the end of the window")
+			}
+		}
+	})
+}
+
+func TestConsistently_Failure(t *testing.T) {
+	t.Run("fails fast when condition fails", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			time.Sleep(1 * durationUnit)
+			conn.WriteJSON("error")
+		}()
+
+		// assert
+		rec.Consistently(ws.NoneToBe("error")).For(10 * durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Consistently should fail because of error message")
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 5*durationUnit {
+				t.Errorf("Consistently should fail faster")
+			}
+		}
+	})
+}
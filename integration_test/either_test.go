@@ -0,0 +1,159 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func okOrError(a *ws.Assertion) *ws.Assertion {
+	return a.OneToBe("req").Either(
+		func(a *ws.Assertion) { a.NextToBe("ok").OneToBe("result") },
+		func(a *ws.Assertion) { a.NextToBe("error") },
+	)
+}
+
+func TestEither_Success(t *testing.T) {
+	t.Run("succeeds when the first branch passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("req")
+			conn.WriteJSON("ok")
+			conn.WriteJSON("progress")
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		okOrError(rec.NewAssertion())
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Either should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds fast when the second branch passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("req")
+			conn.WriteJSON("error")
+		}()
+
+		// assert
+		okOrError(rec.NewAssertion())
+		before := time.Now()
+		rec.RunAssertions(10 * durationUnit)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Either should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else if after.Sub(before) > 5*durationUnit {
+			t.Error("Either should succeed faster")
+		}
+	})
+
+	t.Run("succeeds when the chain goes on after the branch that passed", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("b")
+			conn.WriteJSON("end")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			Either(
+				func(a *ws.Assertion) { a.NextToBe("a").NextToBe("c") },
+				func(a *ws.Assertion) { a.NextToBe("a").NextToBe("b") },
+			).
+			NextToBe("end")
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Either should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with a closing condition in a branch", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("b")
+		}()
+
+		// assert
+		rec.NewAssertion().Either(
+			func(a *ws.Assertion) { a.NoneToBe("b") },
+			func(a *ws.Assertion) { a.LastToBe("b") },
+		)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Either should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestEither_Failure(t *testing.T) {
+	t.Run("fails when no branch passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("req")
+			conn.WriteJSON("timeout")
+		}()
+
+		// assert
+		okOrError(rec.NewAssertion())
+		before := time.Now()
+		rec.RunAssertions(10 * durationUnit)
+		after := time.Now()
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Either should fail because no branch passes")
+		} else if after.Sub(before) > 5*durationUnit {
+			t.Error("Either should fail faster")
+		}
+	})
+
+	t.Run("fails when the chain does not go on after the branch that passed", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("req")
+			conn.WriteJSON("error")
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		okOrError(rec.NewAssertion()).NextToBe("retry")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Either should fail because the chain does not go on")
+		}
+	})
+}
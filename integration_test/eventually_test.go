@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestEventually_Success(t *testing.T) {
+	t.Run("succeeds fast when condition passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			time.Sleep(1 * durationUnit)
+			conn.WriteJSON("ready")
+		}()
+
+		// assert
+		rec.Eventually(ws.OneToBe("ready")).Within(5 * durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Eventually should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 4*durationUnit {
+				t.Errorf("Eventually should succeed faster")
+			}
+		}
+	})
+
+	t.Run("retries failing condition on next messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("loading")
+			conn.WriteJSON("loading")
+			conn.WriteJSON("ready")
+		}()
+
+		// assert
+		rec.Eventually(ws.NextToBe("ready"))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Eventually should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when end-only condition passes on window end", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("loading")
+			conn.WriteJSON("x")
+		}()
+
+		// assert
+		rec.Eventually(ws.LastToBe("x")).Within(2 * durationUnit)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Eventually should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestEventually_Failure(t *testing.T) {
+	t.Run("fails when window is over before condition passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			time.Sleep(4 * durationUnit)
+			conn.WriteJSON("ready")
+		}()
+
+		// assert
+		rec.Eventually(ws.OneToBe("ready")).Within(2 * durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Eventually should fail because of window")
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 4*durationUnit {
+				t.Errorf("Eventually should fail faster")
+			}
+		}
+	})
+
+	t.Run("fails when end-only condition does not pass on window end", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("x")
+			conn.WriteJSON("loading")
+		}()
+
+		// assert
+		rec.Eventually(ws.LastToBe("x")).Within(2 * durationUnit)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Eventually should fail since last message is not x")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	ws "github.com/silently/wsmock"
+)
+
+const pongWait = 60 * time.Second
+
+// closes conn if no pong is received within pongWait, pings when receiving "ping-me"
+func keepaliveHandler(conn *ws.GorillaConn) {
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.WriteJSON("pong received")
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	for {
+		_, p, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if string(p) == "ping-me" {
+			conn.WriteMessage(websocket.PingMessage, nil)
+		}
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	t.Run("long timeout is reached fast when clock auto advances", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		clock := ws.NewFakeClock()
+		clock.AutoAdvance(1 * time.Millisecond)
+		ws.UseClock(mockT, clock)
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("pong")
+
+		// assert
+		rec.NewAssertion().NoneToBe("ping")
+		before := time.Now()
+		rec.RunAssertions(time.Hour)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("NoneToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 5*durationUnit {
+				t.Errorf("NoneToBe should succeed faster with fake clock")
+			}
+		}
+	})
+
+	t.Run("read deadline is exceeded when fake time passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		clock := ws.NewFakeClock()
+		clock.AutoAdvance(1 * time.Millisecond)
+		ws.UseClock(mockT, clock)
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go keepaliveHandler(conn)
+
+		// assert: conn is closed by handler after pongWait, hence before the timeout
+		rec.NewAssertion().NoneToBe("ping")
+		start := clock.Now()
+		rec.RunAssertions(10 * pongWait)
+		elapsed := clock.Now().Sub(start)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("NoneToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+		if elapsed < pongWait || elapsed > 2*pongWait {
+			t.Errorf("conn should be closed after pongWait, fake time elapsed: %v", elapsed)
+		}
+	})
+
+	t.Run("pong handler is called when client answers ping", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		clock := ws.NewFakeClock()
+		ws.UseClock(mockT, clock)
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go keepaliveHandler(conn)
+
+		// script
+		conn.Send("ping-me")
+
+		// assert
+		rec.NewAssertion().OneToBe("pong received")
+		go func() {
+			time.Sleep(1 * durationUnit)
+			clock.Advance(time.Second)
+		}()
+		rec.RunAssertions(time.Second)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
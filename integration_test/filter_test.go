@@ -0,0 +1,124 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func isChat(m any) bool {
+	msg, ok := m.(Message)
+	return ok && msg.Kind == "chat"
+}
+
+func isFromBarbara(m any) bool {
+	msg, ok := m.(Message)
+	return ok && msg.Payload != "" && msg.Payload[0] == 'B'
+}
+
+func TestFilter_Success(t *testing.T) {
+	t.Run("succeeds when NextToBe applies to the filtered stream", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"chat", "hello"})
+			conn.WriteJSON(Message{"heartbeat", ""})
+			conn.WriteJSON(Message{"presence", "Johnny"})
+			conn.WriteJSON(Message{"chat", "bye"})
+		}()
+
+		// assert
+		rec.NewAssertion().Filter(isChat).NextToBe(Message{"chat", "hello"}).NextToBe(Message{"chat", "bye"})
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Filter should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with closing conditions", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"chat", "hello"})
+			conn.WriteJSON(Message{"heartbeat", ""})
+		}()
+
+		// assert
+		rec.NewAssertion().Filter(isChat).AllToCheck(isChat)
+		rec.NewAssertion().Filter(isChat).LastToBe(Message{"chat", "hello"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Filter should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when filters are combined", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"chat", "Johnny: hello"})
+			conn.WriteJSON(Message{"presence", "Barbara"})
+			conn.WriteJSON(Message{"chat", "Barbara: hi"})
+		}()
+
+		// assert
+		rec.NewAssertion().Filter(isChat).Filter(isFromBarbara).NextToBe(Message{"chat", "Barbara: hi"})
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Filter should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestFilter_Failure(t *testing.T) {
+	t.Run("fails when the next filtered message is not equal", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"chat", "hello"})
+			conn.WriteJSON(Message{"heartbeat", ""})
+			conn.WriteJSON(Message{"chat", "hello again"})
+			conn.WriteJSON(Message{"chat", "bye"})
+		}()
+
+		// assert
+		rec.NewAssertion().Filter(isChat).NextToBe(Message{"chat", "hello"}).NextToBe(Message{"chat", "bye"})
+		rec.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Filter should fail because another chat message is received before bye")
+		}
+	})
+
+	t.Run("fails when no message checks the filter", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON(Message{"heartbeat", ""})
+
+		// assert
+		rec.NewAssertion().Filter(isChat).LastToCheck(isChat)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Filter should fail because there is no last chat message")
+		}
+	})
+}
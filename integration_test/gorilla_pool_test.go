@@ -0,0 +1,76 @@
+package integration_test
+
+import (
+	"fmt"
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+// replies to each message with "ack"
+func ackHandler(conn ws.IGorilla) {
+	for {
+		var m any
+		if err := conn.ReadJSON(&m); err != nil {
+			return
+		}
+		conn.WriteJSON("ack")
+	}
+}
+
+func TestGorillaPool(t *testing.T) {
+	t.Run("succeeds when all conns receive replies", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		pool := ws.NewGorillaPool(mockT, 100, ws.WithHandler(ackHandler))
+
+		// script
+		go pool.SendAll("ping")
+
+		// assert
+		pool.Group().Each().OneToBe("ack")
+		ws.RunAssertions(mockT, 10*durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("GorillaPool should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with named conns and idle recorders", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		pool := ws.NewGorillaPool(mockT, 1000, ws.WithNames(func(i int) string { return fmt.Sprintf("user%v", i) }))
+		conn, rec := pool.Named("user42")
+
+		// script
+		go conn.WriteJSON("hello")
+
+		// assert
+		rec.NewAssertion().OneToBe("hello")
+		pool.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("GorillaPool should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+		if pool.Len() != 1000 || len(pool.Recorders()) != 1000 {
+			t.Error("GorillaPool should contain 1000 conns")
+		}
+	})
+
+	t.Run("fails when conns are closed", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		pool := ws.NewGorillaPool(mockT, 10, ws.WithHandler(ackHandler))
+
+		// script
+		go pool.CloseAll()
+
+		// assert
+		pool.Group().Each().OneToBe("ack")
+		pool.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("GorillaPool should fail because conns are closed before replying")
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package integration_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func loadJoinSession(t *testing.T) ws.HARScenario {
+	scenarios, err := ws.LoadHAR("testdata/join_session.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenarios) != 1 || scenarios[0].URL != "wss://example.com/ws" {
+		t.Fatalf("unexpected scenarios: %+v", scenarios)
+	}
+	return scenarios[0]
+}
+
+func TestHAR(t *testing.T) {
+	t.Run("succeeds when handler writes received frames", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		scenario := loadJoinSession(t)
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go joinWithIdHandler(conn)
+
+		// script
+		scenario.Script(conn, ws.WithDecodedJSONText())
+
+		// assert
+		scenario.Expect(rec)
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("HAR scenario should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when handler writes differ from received frames", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		scenario := loadJoinSession(t)
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go ackHandler(conn)
+
+		// script
+		scenario.Script(conn)
+
+		// assert
+		scenario.Expect(rec)
+		rec.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("HAR scenario should fail because handler only writes acks")
+		}
+	})
+
+	t.Run("succeeds when differing fields are ignored", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		scenario := loadJoinSession(t)
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go joinWithIdHandler(conn)
+
+		// script
+		conn.Send(Message{"join", "lobby"}) // shifts ids
+		scenario.Script(conn, ws.WithDecodedJSONText())
+
+		// assert
+		isLobby := func(msg any) bool {
+			return strings.Contains(fmt.Sprintf("%v", msg), "lobby")
+		}
+		scenario.Expect(rec, ws.WithIgnoredFields("$.id"), ws.WithIgnoredMessages(isLobby))
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("HAR scenario should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("replays with recorded timing", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		scenario := loadJoinSession(t)
+
+		// script + assert
+		scenario.Replay(mockT, joinWithIdHandler, ws.WithSpeed(10), ws.WithDecodedJSONText())
+
+		if mockT.Failed() { // fail not expected
+			t.Error("HAR scenario should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
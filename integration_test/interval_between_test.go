@@ -0,0 +1,88 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func isHeartbeat(msg any) bool {
+	return msg == "heartbeat"
+}
+
+func TestIntervalBetween_Success(t *testing.T) {
+	t.Run("succeeds when messages are periodic", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			for i := 0; i < 4; i++ {
+				conn.WriteJSON("heartbeat")
+				conn.WriteJSON("other") // ignored
+				time.Sleep(2 * durationUnit)
+			}
+		}()
+
+		// assert
+		rec.NewAssertion().IntervalBetween(isHeartbeat, 1*durationUnit, 4*durationUnit)
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("IntervalBetween should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestIntervalBetween_Failure(t *testing.T) {
+	t.Run("fails fast when messages are too close", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("heartbeat")
+			conn.WriteJSON("heartbeat")
+		}()
+
+		// assert
+		rec.NewAssertion().IntervalBetween(isHeartbeat, 1*durationUnit, 4*durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if !mockT.Failed() { // fail expected
+			t.Error("IntervalBetween should fail because messages are too close")
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 5*durationUnit {
+				t.Errorf("IntervalBetween should fail faster")
+			}
+		}
+	})
+
+	t.Run("fails when messages are too far apart", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("heartbeat")
+			time.Sleep(5 * durationUnit)
+			conn.WriteJSON("heartbeat")
+		}()
+
+		// assert
+		rec.NewAssertion().IntervalBetween(isHeartbeat, 1*durationUnit, 3*durationUnit)
+		rec.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("IntervalBetween should fail because messages are too far apart")
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func isPing(msg any) bool {
+	return msg == "ping"
+}
+
+func isPong(msg any) bool {
+	return msg == "pong"
+}
+
+// replies "pong" to "ping" after delay
+func delayedPongHandler(conn *ws.GorillaConn, delay time.Duration) {
+	for {
+		var m string
+		if err := conn.ReadJSON(&m); err != nil {
+			return
+		}
+		if m == "ping" {
+			time.Sleep(delay)
+			conn.WriteJSON("pong")
+		}
+	}
+}
+
+func TestLatencyFrom_Success(t *testing.T) {
+	t.Run("succeeds when reply is written fast enough", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go delayedPongHandler(conn, 1*durationUnit)
+
+		// script
+		conn.Send("ping")
+
+		// assert
+		rec.NewAssertion().LatencyFrom(isPing, isPong, 3*durationUnit)
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("LatencyFrom should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestLatencyFrom_Failure(t *testing.T) {
+	t.Run("fails when reply is written too late", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go delayedPongHandler(conn, 4*durationUnit)
+
+		// script
+		conn.Send("ping")
+
+		// assert
+		rec.NewAssertion().LatencyFrom(isPing, isPong, 2*durationUnit)
+		rec.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("LatencyFrom should fail because of late reply")
+		}
+	})
+
+	t.Run("fails when there is no reply", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go delayedPongHandler(conn, 0)
+
+		// script
+		conn.Send("hello")
+
+		// assert
+		rec.NewAssertion().LatencyFrom(isPing, isPong, 2*durationUnit)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("LatencyFrom should fail because there is no reply")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	ws "github.com/silently/wsmock"
+)
+
+func TestMessages(t *testing.T) {
+	t.Run("returns messages of all rounds", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		conn.WriteJSON("a")
+		rec.RunAssertions(1 * durationUnit)
+		conn.WriteJSON("b")
+
+		// assert
+		messages := rec.Messages()
+		if len(messages) != 2 || rec.Len() != 2 {
+			t.Fatalf("expected 2 messages, got %v", messages)
+		}
+		if messages[0].Message != "a" || messages[1].Message != "b" {
+			t.Errorf("unexpected messages: %v", messages)
+		}
+		if messages[1].Time.Before(messages[0].Time) {
+			t.Errorf("timestamps should be ordered: %v", messages)
+		}
+	})
+
+	t.Run("returns the latest message", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// assert empty
+		if _, ok := rec.Last(); ok {
+			t.Error("Last should not return a message")
+		}
+
+		// script
+		before := time.Now()
+		conn.WriteJSON("a")
+		conn.WriteJSON("b")
+
+		// assert
+		last, ok := rec.Last()
+		if !ok || last.Message != "b" || last.Time.Before(before) {
+			t.Errorf("unexpected latest message: %v", last)
+		}
+	})
+
+	t.Run("returns a snapshot", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		conn.WriteJSON("a")
+		messages := rec.Messages()
+		conn.WriteJSON("b")
+
+		// assert
+		if len(messages) != 1 {
+			t.Errorf("snapshot should not change, got %v", messages)
+		}
+	})
+
+	t.Run("returns typed messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		conn.WriteJSON(Message{"join", "room1"})
+		conn.WriteJSON("debug")
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"kind":"chat","payload":"hello"}`))
+		conn.WriteJSON(map[string]any{"kind": "leave", "payload": "room1"})
+
+		// assert
+		messages := ws.MessagesAs[Message](rec)
+		if len(messages) != 3 {
+			t.Fatalf("expected 3 typed messages, got %v", messages)
+		}
+		if messages[0].Message.Kind != "join" || messages[1].Message.Payload != "hello" || messages[2].Message.Kind != "leave" {
+			t.Errorf("unexpected typed messages: %v", messages)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestOptional_Success(t *testing.T) {
+	t.Run("succeeds when the optional condition passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("start")
+			conn.WriteJSON("warning")
+			conn.WriteJSON("end")
+		}()
+
+		// assert
+		rec.NewAssertion().OneToBe("start").Optional(ws.NextToBe("warning")).NextToBe("end")
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Optional should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when the optional condition is skipped", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("start")
+			conn.WriteJSON("end")
+		}()
+
+		// assert
+		rec.NewAssertion().OneToBe("start").Optional(ws.NextToBe("warning")).NextToBe("end")
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Optional should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when optional is the last step", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("start")
+
+		// assert
+		rec.NewAssertion().OneToBe("start").Optional(ws.OneToBe("warning"))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Optional should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestOptional_Failure(t *testing.T) {
+	t.Run("fails when the next condition fails in both cases", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("start")
+			conn.WriteJSON("warning")
+			conn.WriteJSON("warning")
+			conn.WriteJSON("end")
+		}()
+
+		// assert
+		rec.NewAssertion().OneToBe("start").Optional(ws.NextToBe("warning")).NextToBe("end")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Optional should fail because a second warning is received")
+		}
+	})
+}
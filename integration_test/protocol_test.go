@@ -0,0 +1,130 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+type Envelope struct {
+	Kind    string `json:"kind"`
+	Payload any    `json:"payload"`
+}
+
+type Join struct {
+	Room string `json:"room"`
+}
+
+type JoinAck struct {
+	Room  string `json:"room"`
+	Count int    `json:"count"`
+}
+
+func newChatProtocol() *ws.Protocol {
+	p := ws.NewProtocol("kind").Payload("payload")
+	ws.Register[Join](p, "join")
+	ws.Register[JoinAck](p, "join_ack")
+	return p
+}
+
+// acknowledges join messages
+func joinHandler(conn ws.IGorilla) {
+	for {
+		var m struct {
+			Kind    string `json:"kind"`
+			Payload Join   `json:"payload"`
+		}
+		if err := conn.ReadJSON(&m); err != nil {
+			return
+		}
+		if m.Kind == "join" {
+			conn.WriteJSON(Envelope{"join_ack", JoinAck{m.Payload.Room, 1}})
+		} else {
+			conn.WriteJSON(Envelope{"error", m.Kind})
+		}
+	}
+}
+
+func TestProtocol_Success(t *testing.T) {
+	t.Run("succeeds when next message has the expected kind", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.UseProtocol(newChatProtocol())
+		go joinHandler(conn)
+
+		// script
+		conn.Send(Envelope{"join", Join{"lobby"}})
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.NextToBeKind(func(a JoinAck) bool {
+			return a.Room == "lobby"
+		}))
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("NextToBeKind should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with decoded sent messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.UseProtocol(newChatProtocol())
+		go joinHandler(conn)
+
+		// script
+		conn.Send(Envelope{"join", Join{"lobby"}})
+
+		// assert
+		isJoin := func(m any) bool { _, ok := m.(Join); return ok }
+		isJoinAck := func(m any) bool { _, ok := m.(JoinAck); return ok }
+		rec.NewAssertion().LatencyFrom(isJoin, isJoinAck, 3*durationUnit)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("LatencyFrom should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestProtocol_Failure(t *testing.T) {
+	t.Run("fails when next message has another kind", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.UseProtocol(newChatProtocol())
+		go joinHandler(conn)
+
+		// script
+		conn.Send(Envelope{"join", Join{"lobby"}})
+
+		// assert
+		rec.NewAssertion().WithCondition(ws.NextToBeKind[Join](nil))
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("NextToBeKind should fail")
+		}
+	})
+
+	t.Run("fails when an unknown kind is rejected", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.UseProtocol(newChatProtocol().RejectUnknown())
+		go joinHandler(conn)
+
+		// script
+		conn.Send(Envelope{"leave", Join{"lobby"}})
+
+		// assert
+		rec.NewAssertion().OneToCheck(func(m any) bool { return true })
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("unknown kind should be reported")
+		}
+	})
+}
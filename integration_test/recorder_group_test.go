@@ -0,0 +1,118 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestRecorderGroup_Success(t *testing.T) {
+	t.Run("succeeds when each member gets the broadcast exactly once and others never do", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn1, rec1 := ws.NewGorillaMockAndRecorder(mockT)
+		conn2, rec2 := ws.NewGorillaMockAndRecorder(mockT)
+		conn3, rec3 := ws.NewGorillaMockAndRecorder(mockT)
+		room := ws.NewRecorderGroup(rec1, rec2)
+		others := ws.NewRecorderGroup(rec3)
+
+		// script
+		go func() {
+			conn1.WriteJSON("broadcast")
+			conn2.WriteJSON("broadcast")
+			conn3.WriteJSON("lobby")
+		}()
+
+		// assert
+		room.Each().OneToBe("broadcast").NoneToBe("broadcast")
+		others.None().OneToBe("broadcast")
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("RecorderGroup should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when run at the group level", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn1, rec1 := ws.NewGorillaMockAndRecorder(mockT)
+		conn2, rec2 := ws.NewGorillaMockAndRecorder(mockT)
+		room := ws.NewRecorderGroup(rec1)
+		room.Add(rec2)
+
+		// script
+		go func() {
+			conn1.WriteJSON("broadcast")
+			conn2.WriteJSON("broadcast")
+		}()
+
+		// assert
+		room.Each().OneToBe("broadcast")
+		room.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("RecorderGroup should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestRecorderGroup_Failure(t *testing.T) {
+	t.Run("fails when a member misses the broadcast", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn1, rec1 := ws.NewGorillaMockAndRecorder(mockT)
+		_, rec2 := ws.NewGorillaMockAndRecorder(mockT)
+		room := ws.NewRecorderGroup(rec1, rec2)
+
+		// script
+		go conn1.WriteJSON("broadcast")
+
+		// assert
+		room.Each().OneToBe("broadcast")
+		room.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("RecorderGroup should fail because the second member misses the broadcast")
+		}
+	})
+
+	t.Run("fails when a member gets the broadcast twice", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn1, rec1 := ws.NewGorillaMockAndRecorder(mockT)
+		room := ws.NewRecorderGroup(rec1)
+
+		// script
+		go func() {
+			conn1.WriteJSON("broadcast")
+			conn1.WriteJSON("broadcast")
+		}()
+
+		// assert
+		room.Each().OneToBe("broadcast").NoneToBe("broadcast")
+		room.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("RecorderGroup should fail because the broadcast is received twice")
+		}
+	})
+
+	t.Run("fails when a non-member gets the broadcast", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn1, rec1 := ws.NewGorillaMockAndRecorder(mockT)
+		others := ws.NewRecorderGroup(rec1)
+
+		// script
+		go conn1.WriteJSON("broadcast")
+
+		// assert
+		others.None().OneToBe("broadcast")
+		others.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("RecorderGroup should fail because a non-member gets the broadcast")
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func isProgress(m any) bool {
+	s, ok := m.(string)
+	return ok && s == "progress"
+}
+
+func isDone(m any) bool {
+	s, ok := m.(string)
+	return ok && s == "done"
+}
+
+func anything(_ any) bool {
+	return true
+}
+
+func TestRepeat(t *testing.T) {
+	t.Run("succeeds when the condition passes n times", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("tick")
+			conn.WriteJSON("other")
+			conn.WriteJSON("tick")
+			conn.WriteJSON("tick")
+		}()
+
+		// assert
+		rec.NewAssertion().Repeat(3, ws.OneToBe("tick"))
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Repeat should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when the condition passes less than n times", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("tick")
+			conn.WriteJSON("tick")
+		}()
+
+		// assert
+		rec.NewAssertion().Repeat(3, ws.OneToBe("tick"))
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Repeat should fail because the condition only passes twice")
+		}
+	})
+}
+
+func TestZeroOrMore(t *testing.T) {
+	t.Run("succeeds with progress messages, then done and nothing else", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("progress")
+			conn.WriteJSON("done")
+		}()
+
+		// assert
+		rec.NewAssertion().ZeroOrMore(isProgress).NextToCheck(isDone).NoneToCheck(anything)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("ZeroOrMore should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds without progress messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("done")
+
+		// assert
+		rec.NewAssertion().ZeroOrMore(isProgress).NextToCheck(isDone)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("ZeroOrMore should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when another message is received", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("debug")
+			conn.WriteJSON("done")
+		}()
+
+		// assert
+		rec.NewAssertion().ZeroOrMore(isProgress).NextToCheck(isDone)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("ZeroOrMore should fail because of the debug message")
+		}
+	})
+
+	t.Run("fails when something follows done", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("done")
+			conn.WriteJSON("progress")
+		}()
+
+		// assert
+		rec.NewAssertion().ZeroOrMore(isProgress).NextToCheck(isDone).NoneToCheck(anything)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("ZeroOrMore should fail because of the message following done")
+		}
+	})
+}
+
+func TestOneOrMore(t *testing.T) {
+	t.Run("succeeds with several progress messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("progress")
+			conn.WriteJSON("done")
+		}()
+
+		// assert
+		rec.NewAssertion().OneOrMore(isProgress).NextToCheck(isDone)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneOrMore should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails without progress messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON("done")
+
+		// assert
+		rec.NewAssertion().OneOrMore(isProgress).NextToCheck(isDone)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("OneOrMore should fail because no progress message is received")
+		}
+	})
+}
+
+func TestUntil(t *testing.T) {
+	t.Run("succeeds from any message checking the predicate", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("done")
+			conn.WriteJSON("done")
+			conn.WriteJSON("bye")
+		}()
+
+		// assert
+		rec.NewAssertion().Until(isDone).NextToBe("bye")
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Until should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when no message checks the predicate", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("bye")
+		}()
+
+		// assert
+		rec.NewAssertion().Until(isDone).NextToBe("bye")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Until should fail because no done message is received")
+		}
+	})
+}
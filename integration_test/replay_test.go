@@ -0,0 +1,185 @@
+package integration_test
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+// records a session of joinWithIdHandler and reads it back from its JSON Lines export
+func recordJoinTranscript(t *testing.T) ws.TranscriptEvents {
+	mockT := &testing.T{}
+	conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+	go joinWithIdHandler(conn)
+
+	conn.Send(Message{"join", "room1"})
+	conn.Send(Message{"join", "room2"})
+
+	rec.NewAssertion().OneToBe(Message{"chat", "welcome to room2"})
+	rec.RunAssertions(10 * durationUnit)
+
+	var buf bytes.Buffer
+	if err := rec.Transcript().WriteJSONL(&buf); err != nil {
+		t.Fatal(err)
+	}
+	transcript, err := ws.ReadTranscript(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transcript
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("succeeds when handler writes recorded messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		transcript := recordJoinTranscript(t)
+
+		// script + assert
+		ws.Replay(mockT, joinWithIdHandler, transcript, ws.WithGrace(10*durationUnit))
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Replay should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with compressed timing", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		transcript := recordJoinTranscript(t)
+
+		// script + assert
+		ws.Replay(mockT, joinWithIdHandler, transcript, ws.WithCompressedTiming(), ws.WithGrace(10*durationUnit))
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Replay should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when handler writes differ from recorded messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		transcript := recordJoinTranscript(t)
+
+		// script + assert
+		ws.Replay(mockT, joinHandler, transcript, ws.WithCompressedTiming(), ws.WithGrace(10*durationUnit))
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Replay should fail because handler does not write ids")
+		}
+	})
+
+	t.Run("fails when handler writes more than recorded messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		transcript := recordJoinTranscript(t)
+
+		// script + assert
+		ws.Replay(mockT, func(conn ws.IGorilla) {
+			for id := 1; ; id++ {
+				var m Message
+				if err := conn.ReadJSON(&m); err != nil {
+					return
+				}
+				conn.WriteJSON(map[string]any{"kind": "join_ack", "room": m.Payload, "id": id})
+				conn.WriteJSON(Message{"chat", "welcome to " + m.Payload})
+				if id == 2 {
+					conn.WriteJSON(Message{"chat", "extra"})
+				}
+			}
+		}, transcript, ws.WithCompressedTiming(), ws.WithGrace(10*durationUnit))
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Replay should fail because handler writes an extra message")
+		}
+	})
+
+	t.Run("succeeds when differing fields are ignored", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		transcript := recordJoinTranscript(t)
+		for i := range transcript { // simulates ids from another run
+			if m, ok := transcript[i].Message.(map[string]any); ok && m["id"] != nil {
+				m["id"] = 42
+			}
+		}
+
+		// script + assert
+		ws.Replay(mockT, joinWithIdHandler, transcript, ws.WithCompressedTiming(), ws.WithGrace(10*durationUnit), ws.WithIgnoredFields("$.id"))
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Replay should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when differing messages are ignored", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		transcript := recordJoinTranscript(t)
+		isAck := func(msg any) bool {
+			if m, ok := msg.(map[string]any); ok {
+				return m["kind"] == "join_ack"
+			}
+			return false
+		}
+
+		// script + assert
+		ws.Replay(mockT, func(conn ws.IGorilla) {
+			for {
+				var m Message
+				if err := conn.ReadJSON(&m); err != nil {
+					return
+				}
+				conn.WriteJSON(map[string]any{"kind": "join_ack"}) // differs from recorded ack
+				conn.WriteJSON(Message{"chat", "welcome to " + m.Payload})
+			}
+		}, transcript, ws.WithCompressedTiming(), ws.WithGrace(10*durationUnit), ws.WithIgnoredMessages(isAck))
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Replay should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("reads transcript from JSON Lines", func(t *testing.T) {
+		jsonl := `{"time":"2024-01-01T00:00:00Z","conn":"alice","event":"send","type":"text","message":"hello"}
+{"time":"2024-01-01T00:00:00.01Z","conn":"alice","event":"write","type":"json","message":"ack"}
+`
+		transcript, err := ws.ReadTranscript(strings.NewReader(jsonl))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transcript) != 2 || transcript[1].Message != "ack" {
+			t.Fatalf("unexpected transcript: %#v", transcript)
+		}
+
+		// script + assert
+		mockT := &testing.T{}
+		ws.Replay(mockT, ackHandler, transcript, ws.WithGrace(10*durationUnit))
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Replay should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("stops sending when handler stops reading", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		var transcript ws.TranscriptEvents
+		for i := 0; i < 1000; i++ { // more than a conn can buffer
+			transcript = append(transcript, ws.TranscriptEvent{Conn: "alice", Event: "send", Type: "text", Message: "hello"})
+		}
+		before := runtime.NumGoroutine()
+
+		// script + assert
+		ws.Replay(mockT, func(conn ws.IGorilla) {}, transcript, ws.WithCompressedTiming(), ws.WithGrace(10*durationUnit))
+
+		time.Sleep(10 * durationUnit)
+		if after := runtime.NumGoroutine(); after > before {
+			t.Errorf("Replay should not leak goroutines, before: %d, after: %d", before, after)
+		}
+	})
+}
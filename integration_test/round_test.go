@@ -35,3 +35,148 @@ func TestRound(t *testing.T) {
 		}
 	})
 }
+
+func TestRound_Policy(t *testing.T) {
+	t.Run("carries over messages written between rounds by default", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// first round
+		rec.NewAssertion().OneToBe("ping")
+		go conn.WriteJSON("ping")
+		rec.RunAssertions(5 * durationUnit)
+
+		// script (between rounds)
+		conn.WriteJSON("pong")
+
+		// assert
+		rec.NewAssertion().OneToBe("pong")
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToBe should succeed with CarryOver, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("drops messages written between rounds with Drop", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.SetRoundPolicy(ws.Drop)
+		rec.RunAssertions(1 * durationUnit)
+
+		// script
+		conn.WriteJSON("stale")
+		rec.BeginRound()
+		conn.WriteJSON("fresh")
+
+		// assert
+		rec.NewAssertion().NextToBe("fresh")
+		rec.NewAssertion().NoneToBe("stale")
+		rec.RunAssertions(2 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("stale message should be dropped, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when messages are written between rounds with Fail", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.SetRoundPolicy(ws.Fail)
+		rec.RunAssertions(1 * durationUnit)
+
+		// script
+		conn.WriteJSON("unexpected")
+
+		// assert
+		rec.NewAssertion().NoneToBe("unexpected")
+		rec.RunAssertions(2 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("round should fail when a message is written between rounds")
+		}
+	})
+
+	t.Run("does not fail with Fail when messages are written during rounds", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.SetRoundPolicy(ws.Fail)
+		rec.RunAssertions(1 * durationUnit)
+
+		// script
+		rec.BeginRound()
+		conn.WriteJSON("expected")
+
+		// assert
+		rec.NewAssertion().OneToBe("expected")
+		rec.RunAssertions(2 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("OneToBe should succeed after BeginRound, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("messages written after EndRound belong to the next round", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		conn.WriteJSON("first")
+		rec.EndRound()
+		conn.WriteJSON("second")
+
+		// assert
+		rec.NewAssertion().OneToBe("first").NoneToBe("second")
+		rec.RunAssertions(2 * durationUnit)
+		rec.NewAssertion().NextToBe("second")
+		rec.RunAssertions(2 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("second message should be carried to the next round, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("no message is lost or duplicated across many rounds", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		count := 500
+
+		// script
+		go func() {
+			for i := 0; i < count; i++ {
+				conn.WriteJSON(i)
+				if i%10 == 0 {
+					time.Sleep(durationUnit / 10)
+				}
+			}
+		}()
+
+		// assert
+		var seen []any
+		for round := 0; round < 200 && len(seen) < count; round++ {
+			rec.NewAssertion().AllToCheck(func(m any) bool {
+				seen = append(seen, m)
+				return true
+			})
+			rec.RunAssertions(durationUnit / 2)
+		}
+
+		if len(seen) != count {
+			t.Fatalf("expected %v messages across rounds, got %v", count, len(seen))
+		}
+		for i, m := range seen {
+			if m != i {
+				t.Fatalf("message #%v should be %v, got %v", i, i, m)
+			}
+		}
+		if mockT.Failed() { // fail not expected
+			t.Error("AllToCheck should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestSession_Success(t *testing.T) {
+	// session assertions are checked when the (sub)test finishes, so t is given to the recorder
+	t.Run("succeeds when no private message leaks during the whole session", func(t *testing.T) {
+		// init
+		conn, rec := ws.NewGorillaMockAndRecorder(t)
+		rec.Session().NoneToCheck(func(m any) bool {
+			msg, ok := m.(Message)
+			return ok && msg.Kind == "private"
+		})
+
+		// script & assert (several rounds)
+		go conn.WriteJSON(Message{"chat", "hello"})
+		rec.NewAssertion().OneToBe(Message{"chat", "hello"})
+		rec.RunAssertions(5 * durationUnit)
+
+		go conn.WriteJSON(Message{"chat", "bye"})
+		rec.NewAssertion().OneToBe(Message{"chat", "bye"})
+		rec.RunAssertions(5 * durationUnit)
+	})
+
+	t.Run("succeeds when a sequence spans several rounds", func(t *testing.T) {
+		// init
+		conn, rec := ws.NewGorillaMockAndRecorder(t)
+		rec.Session().NextToBe("joined").OneToBe("left")
+
+		// script & assert
+		go conn.WriteJSON("joined")
+		rec.NewAssertion().OneToBe("joined")
+		rec.RunAssertions(5 * durationUnit)
+
+		go conn.WriteJSON("left")
+		rec.NewAssertion().OneToBe("left")
+		rec.RunAssertions(5 * durationUnit)
+	})
+}
@@ -0,0 +1,141 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestSetToBe_Success(t *testing.T) {
+	t.Run("succeeds when all messages are received in order", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"joined", "Johnny"})
+			conn.WriteJSON(Message{"joined", "Micheline"})
+			conn.WriteJSON(Message{"joined", "Barbara"})
+		}()
+
+		// assert
+		rec.NewAssertion().SetToBe(
+			Message{"joined", "Johnny"},
+			Message{"joined", "Micheline"},
+			Message{"joined", "Barbara"},
+		)
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("SetToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds fast when all messages are received in any order among others", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			time.Sleep(1 * durationUnit)
+			conn.WriteJSON(Message{"joined", "Barbara"})
+			conn.WriteJSON(Message{"chat", "hello"})
+			conn.WriteJSON(Message{"joined", "Johnny"})
+			conn.WriteJSON(Message{"joined", "Micheline"})
+		}()
+
+		// assert
+		rec.NewAssertion().SetToBe(
+			Message{"joined", "Johnny"},
+			Message{"joined", "Micheline"},
+			Message{"joined", "Barbara"},
+		)
+		before := time.Now()
+		rec.RunAssertions(10 * durationUnit)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("SetToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 5*durationUnit {
+				t.Errorf("SetToBe should succeed faster")
+			}
+		}
+	})
+
+	t.Run("succeeds when chained", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("start")
+			conn.WriteJSON("b")
+			conn.WriteJSON("a")
+			conn.WriteJSON("end")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			NextToBe("start").
+			SetToBe("a", "b").
+			NextToBe("end")
+		rec.RunAssertions(10 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("SetToBe should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestSetToBe_Failure(t *testing.T) {
+	t.Run("fails when a message is missing", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"joined", "Micheline"})
+			conn.WriteJSON(Message{"joined", "Johnny"})
+		}()
+
+		// assert
+		rec.NewAssertion().SetToBe(
+			Message{"joined", "Johnny"},
+			Message{"joined", "Micheline"},
+			Message{"joined", "Barbara"},
+		)
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("SetToBe should fail because a message is missing")
+		}
+	})
+
+	t.Run("fails when the same message is expected twice but received once", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("b")
+		}()
+
+		// assert
+		rec.NewAssertion().SetToBe("a", "a")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("SetToBe should fail because each message can only be matched once")
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+// acknowledges join messages with a volatile id
+func joinWithIdHandler(conn ws.IGorilla) {
+	id := 0
+	for {
+		var m Message
+		if err := conn.ReadJSON(&m); err != nil {
+			return
+		}
+		id++
+		conn.WriteJSON(map[string]any{"kind": "join_ack", "room": m.Payload, "id": id})
+		conn.WriteJSON(Message{"chat", "welcome to " + m.Payload})
+	}
+}
+
+func TestMatchSnapshot(t *testing.T) {
+	t.Run("succeeds when transcript matches golden file", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go joinWithIdHandler(conn)
+
+		// script
+		conn.Send(Message{"join", "lobby"})
+
+		// assert
+		rec.MatchSnapshot(mockT, "testdata/join_flow.golden", "$.id")
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("MatchSnapshot should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("fails when transcript differs from golden file", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		go joinWithIdHandler(conn)
+
+		// script
+		conn.Send(Message{"join", "kitchen"})
+
+		// assert
+		rec.MatchSnapshot(mockT, "testdata/join_flow.golden", "$.id")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("MatchSnapshot should fail")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestStrict_Success(t *testing.T) {
+	t.Run("succeeds when each message is explained", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		// script
+		go func() {
+			conn.WriteJSON("start")
+			conn.WriteJSON("progress")
+			conn.WriteJSON("progress")
+			conn.WriteJSON("done")
+		}()
+
+		// assert
+		rec.NewAssertion().NextToBe("start").ZeroOrMore(isProgress).NextToCheck(isDone)
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Strict should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when messages are explained by different assertions", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		// script
+		go func() {
+			conn.WriteJSON("b")
+			conn.WriteJSON("a")
+			conn.WriteJSON("c")
+		}()
+
+		// assert
+		rec.NewAssertion().SetToBe("a", "b")
+		rec.NewAssertion().LastToBe("c")
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Strict should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with All* conditions", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		// script
+		go func() {
+			conn.WriteJSON("progress")
+			conn.WriteJSON("progress")
+		}()
+
+		// assert
+		rec.NewAssertion().AllToBe("progress")
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Strict should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestStrict_Failure(t *testing.T) {
+	t.Run("fails when a message is skipped", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		// script
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("debug")
+			conn.WriteJSON("b")
+		}()
+
+		// assert
+		rec.NewAssertion().OneToBe("a").OneToBe("b")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Strict should fail because the debug message is not explained")
+		}
+	})
+
+	t.Run("fails when a message is received after the assertion passed", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		// script
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("a")
+		}()
+
+		// assert
+		rec.NewAssertion().OneToBe("a")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Strict should fail because the duplicated message is not explained")
+		}
+	})
+
+	t.Run("fails when messages are only checked by None* conditions", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		// script
+		go conn.WriteJSON("debug")
+
+		// assert
+		rec.NewAssertion().NoneToBe("error")
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Strict should fail because NoneToBe does not explain messages")
+		}
+	})
+}
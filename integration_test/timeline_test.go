@@ -0,0 +1,132 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func isBroadcast(m any) bool {
+	s, ok := m.(string)
+	return ok && s == "broadcast"
+}
+
+func isAck(m any) bool {
+	s, ok := m.(string)
+	return ok && s == "ack"
+}
+
+func TestTimeline_Success(t *testing.T) {
+	t.Run("succeeds when a message is received before another on a different recorder", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		connA, recA := ws.NewGorillaMockAndRecorder(mockT)
+		connB, recB := ws.NewGorillaMockAndRecorder(mockT)
+		ws.Timeline(mockT).Expect(recA, isBroadcast).Before(recB, isAck)
+		ws.Timeline(mockT).Expect(recB, isAck).After(recA, isBroadcast)
+
+		// script
+		go func() {
+			connA.WriteJSON("broadcast")
+			time.Sleep(1 * durationUnit)
+			connB.WriteJSON("ack")
+		}()
+
+		// assert
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Timeline should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds when recorders receive common messages in the same order", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		connA, recA := ws.NewGorillaMockAndRecorder(mockT)
+		connB, recB := ws.NewGorillaMockAndRecorder(mockT)
+		ws.Timeline(mockT).SameOrder(recA, recB)
+
+		// script
+		go func() {
+			connA.WriteJSON("1")
+			connB.WriteJSON("1")
+			connA.WriteJSON("private")
+			connB.WriteJSON("2")
+			connA.WriteJSON("2")
+		}()
+
+		// assert
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Timeline should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestTimeline_Failure(t *testing.T) {
+	t.Run("fails when a message is received after another on a different recorder", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		connA, recA := ws.NewGorillaMockAndRecorder(mockT)
+		connB, recB := ws.NewGorillaMockAndRecorder(mockT)
+		ws.Timeline(mockT).Expect(recA, isBroadcast).Before(recB, isAck)
+
+		// script
+		go func() {
+			connB.WriteJSON("ack")
+			time.Sleep(1 * durationUnit)
+			connA.WriteJSON("broadcast")
+		}()
+
+		// assert
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Timeline should fail because broadcast is received after ack")
+		}
+	})
+
+	t.Run("fails when a message is missing", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		connA, recA := ws.NewGorillaMockAndRecorder(mockT)
+		_, recB := ws.NewGorillaMockAndRecorder(mockT)
+		ws.Timeline(mockT).Expect(recA, isBroadcast).Before(recB, isAck)
+
+		// script
+		go connA.WriteJSON("broadcast")
+
+		// assert
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Timeline should fail because ack is missing")
+		}
+	})
+
+	t.Run("fails when recorders receive common messages in different orders", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		connA, recA := ws.NewGorillaMockAndRecorder(mockT)
+		connB, recB := ws.NewGorillaMockAndRecorder(mockT)
+		ws.Timeline(mockT).SameOrder(recA, recB)
+
+		// script
+		go func() {
+			connA.WriteJSON("1")
+			connA.WriteJSON("2")
+			connB.WriteJSON("2")
+			connB.WriteJSON("1")
+		}()
+
+		// assert
+		ws.RunAssertions(mockT, 5*durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Timeline should fail because messages are not in the same order")
+		}
+	})
+}
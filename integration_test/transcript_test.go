@@ -0,0 +1,62 @@
+package integration_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestTranscript(t *testing.T) {
+	t.Run("exports events of all conns as JSON Lines", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		pool := ws.NewGorillaPool(mockT, 2, ws.WithHandler(ackHandler))
+
+		// script
+		conn0, rec0 := pool.Get(0)
+		conn1, rec1 := pool.Get(1)
+		conn0.Send("hello")
+		conn1.Send("hi")
+
+		// assert
+		rec0.NewAssertion().OneToBe("ack")
+		rec1.NewAssertion().OneToBe("ack")
+		pool.RunAssertions(5 * durationUnit)
+		var buf bytes.Buffer
+		if err := ws.Transcript(mockT).WriteJSONL(&buf); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 6 { // send, read and write on each conn
+			t.Fatalf("expected 6 events, got:\n%v", buf.String())
+		}
+		if !strings.Contains(lines[0], `"conn":"recorder#0","event":"send","type":"text","message":"hello"`) {
+			t.Errorf("unexpected first event: %v", lines[0])
+		}
+	})
+
+	t.Run("does not save transcript when test succeeds", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "transcript.jsonl")
+		t.Run("succeeding test", func(t *testing.T) {
+			// init
+			conn, rec := ws.NewGorillaMockAndRecorder(t)
+			ws.SaveTranscriptOnFailure(t, path)
+			go ackHandler(conn)
+
+			// script
+			conn.Send("hello")
+
+			// assert
+			rec.NewAssertion().OneToBe("ack")
+			rec.RunAssertions(5 * durationUnit)
+		})
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("transcript should not be saved")
+		}
+	})
+}
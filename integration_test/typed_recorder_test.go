@@ -0,0 +1,161 @@
+package integration_test
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	ws "github.com/silently/wsmock"
+)
+
+func TestTypedRecorder_Success(t *testing.T) {
+	t.Run("succeeds with Go values", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"join", "room1"})
+			conn.WriteJSON(Message{"chat", "hello"})
+		}()
+
+		// assert
+		ws.As[Message](rec).NewAssertion().
+			NextToBe(Message{"join", "room1"}).
+			OneToCheck(func(m Message) bool { return m.Kind == "chat" })
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("typed assertion should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with JSON text and binary messages", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"kind":"join","payload":"room1"}`))
+			conn.WriteMessage(websocket.BinaryMessage, []byte(`{"kind":"chat","payload":"hello"}`))
+		}()
+
+		// assert
+		ws.As[Message](rec).NewAssertion().SetToBe(Message{"chat", "hello"}, Message{"join", "room1"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("typed assertion should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with other Go values converted through JSON", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON(map[string]string{"kind": "chat", "payload": "hello"})
+
+		// assert
+		ws.As[Message](rec).NewAssertion().OneToBe(Message{"chat", "hello"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("typed assertion should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with types that are not comparable", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+		type Roster struct {
+			Room    string   `json:"room"`
+			Members []string `json:"members"`
+		}
+
+		// script
+		go func() {
+			conn.WriteJSON(Roster{"room1", []string{"alice"}})
+			conn.WriteJSON(map[string]any{"kind": "chat", "payload": "hello"})
+			conn.WriteJSON(Roster{"room1", []string{"alice", "bob"}})
+		}()
+
+		// assert
+		ws.As[Roster](rec).NewAssertion().
+			Filter(func(r Roster) bool { return r.Room != "" }).
+			NextToBe(Roster{"room1", []string{"alice"}}).
+			LastToBe(Roster{"room1", []string{"alice", "bob"}})
+		ws.As[map[string]any](rec).NewAssertion().OneToBe(map[string]any{"kind": "chat", "payload": "hello"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("typed assertion should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("succeeds with typed filter", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON(Message{"chat", "hello"})
+			conn.WriteJSON(Message{"debug", "..."})
+			conn.WriteJSON(Message{"chat", "bye"})
+		}()
+
+		// assert
+		ws.As[Message](rec).NewAssertion().
+			Filter(func(m Message) bool { return m.Kind == "chat" }).
+			NextToBe(Message{"chat", "hello"}).
+			NextToBe(Message{"chat", "bye"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("typed assertion should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+}
+
+func TestTypedRecorder_Failure(t *testing.T) {
+	t.Run("fails when a message can't be decoded", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteMessage(websocket.TextMessage, []byte("not json"))
+			conn.WriteJSON(Message{"chat", "hello"})
+		}()
+
+		// assert
+		ws.As[Message](rec).NewAssertion().OneToBe(Message{"chat", "hello"})
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("typed assertion should fail on decoding error")
+		}
+	})
+
+	t.Run("fails when typed predicate is not checked", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go conn.WriteJSON(Message{"chat", "hello"})
+
+		// assert
+		ws.As[Message](rec).NewAssertion().NextToCheck(func(m Message) bool { return m.Kind == "join" })
+		rec.RunAssertions(5 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("typed assertion should fail")
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	ws "github.com/silently/wsmock"
+)
+
+func TestWithin_Success(t *testing.T) {
+	t.Run("succeeds when each condition is done within its window", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			time.Sleep(1 * durationUnit)
+			conn.WriteJSON("ack")
+			time.Sleep(4 * durationUnit)
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			OneToBe("ack").Within(3 * durationUnit).
+			OneToBe("result").Within(6 * durationUnit)
+		rec.RunAssertions(20 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Within should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("window starts when previous condition passes", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			time.Sleep(4 * durationUnit)
+			conn.WriteJSON("ack")
+			time.Sleep(2 * durationUnit)
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			OneToBe("ack").
+			OneToBe("result").Within(4 * durationUnit)
+		rec.RunAssertions(20 * durationUnit)
+
+		if mockT.Failed() { // fail not expected
+			t.Error("Within should succeed, mockT output is:\n", getTestOutput(mockT))
+		}
+	})
+
+	t.Run("closing condition succeeds when window is over", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("ack")
+			time.Sleep(4 * durationUnit)
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			OneToBe("ack").
+			NoneWithin(2 * durationUnit).
+			NextToBe("result")
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if mockT.Failed() { // fail not expected
+			t.Error("NoneWithin should succeed, mockT output is:\n", getTestOutput(mockT))
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 10*durationUnit {
+				t.Errorf("NoneWithin should succeed faster")
+			}
+		}
+	})
+}
+
+func TestWithin_Failure(t *testing.T) {
+	t.Run("fails fast when condition is not done within its window", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("ack")
+			time.Sleep(5 * durationUnit)
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			OneToBe("ack").Within(2 * durationUnit).
+			OneToBe("result").Within(2 * durationUnit)
+		before := time.Now()
+		rec.RunAssertions(20 * durationUnit)
+		after := time.Now()
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Within should fail because result is too late")
+		} else {
+			// test timing
+			elapsed := after.Sub(before)
+			if elapsed > 5*durationUnit {
+				t.Errorf("Within should fail faster")
+			}
+		}
+	})
+
+	t.Run("NoneWithin fails when a message is received during window", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("ack")
+			time.Sleep(1 * durationUnit)
+			conn.WriteJSON("result")
+		}()
+
+		// assert
+		rec.NewAssertion().
+			NextToBe("ack").
+			NoneWithin(3 * durationUnit)
+		rec.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("NoneWithin should fail because of received message")
+		}
+	})
+
+	t.Run("fails when window is set on a structural step", func(t *testing.T) {
+		// init
+		mockT := &testing.T{}
+		conn, rec := ws.NewGorillaMockAndRecorder(mockT)
+
+		// script
+		go func() {
+			conn.WriteJSON("ack")
+			conn.WriteJSON("ack")
+		}()
+
+		// assert
+		rec.NewAssertion().Repeat(2, ws.NextToBe("ack")).Within(5 * durationUnit)
+		rec.RunAssertions(10 * durationUnit)
+
+		if !mockT.Failed() { // fail expected
+			t.Error("Within should fail because it is not supported on Repeat")
+		}
+	})
+}
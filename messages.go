@@ -0,0 +1,65 @@
+package wsmock
+
+import "time"
+
+// A TypedRecord is a Record whose message has type T (see MessagesAs)
+type TypedRecord[T any] struct {
+	Message T
+	Time    time.Time
+}
+
+// called when the server handler writes to the corresponding conn
+func (r *Recorder) addToHistory(w Record) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	r.history = append(r.history, w)
+}
+
+func (r *Recorder) getHistory() []Record {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	return append([]Record(nil), r.history...)
+}
+
+// API
+
+// Returns a snapshot of all the messages written to the recorder since its creation (not only during
+// the current round), with the time they were recorded at.
+//
+// It's useful to make assertions with plain Go (or any assertion library) when conditions don't fit.
+func (r *Recorder) Messages() []Record {
+	return r.getHistory()
+}
+
+// Returns the number of messages written to the recorder since its creation
+func (r *Recorder) Len() int {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	return len(r.history)
+}
+
+// Returns the latest message written to the recorder, ok being false if there is none
+func (r *Recorder) Last() (w Record, ok bool) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	return last(r.history)
+}
+
+// Returns a snapshot of the messages written to the recorder since its creation decoded into T, with the time
+// they were recorded at. Messages are decoded like in typed recorders (see As), and skipped if they can't be.
+//
+// For instance `wsmock.MessagesAs[Message](rec)` if the handler writes Message structs with WriteJSON, or JSON
+// text frames with WriteMessage.
+func MessagesAs[T any](r *Recorder) []TypedRecord[T] {
+	var typed []TypedRecord[T]
+	for _, w := range r.getHistory() {
+		if m, err := decode[T](w.Message); err == nil {
+			typed = append(typed, TypedRecord[T]{m.(T), w.Time})
+		}
+	}
+	return typed
+}
@@ -1,7 +1,7 @@
 package wsmock
 
 import (
-	"encoding/json"
+	"reflect"
 	"regexp"
 	"strings"
 )
@@ -20,11 +20,16 @@ func eq(target any) Predicate {
 	}
 }
 
+// like eq, for values that may not be comparable with `==` (see TypedAssertion)
+func deepEq(target any) Predicate {
+	return func(msg any) bool {
+		return reflect.DeepEqual(msg, target)
+	}
+}
+
 func contain(sub string) Predicate {
 	return func(msg any) bool {
-		if str, ok := msg.(string); ok {
-			return strings.Contains(str, sub)
-		} else if b, err := json.Marshal(msg); err == nil {
+		if b, ok := textOf(msg); ok {
 			return strings.Contains(string(b), sub)
 		}
 		return false
@@ -33,17 +38,40 @@ func contain(sub string) Predicate {
 
 func match(re *regexp.Regexp) Predicate {
 	return func(msg any) bool {
-		if str, ok := msg.(string); ok {
-			return re.MatchString(str)
-		} else if b, err := json.Marshal(msg); err == nil {
+		if b, ok := textOf(msg); ok {
 			return re.Match(b)
 		}
 		return false
 	}
 }
 
-func not(f Predicate) Predicate {
+// Returns a Predicate that is true if f is false
+func Not(f Predicate) Predicate {
 	return func(msg any) bool {
 		return !f(msg)
 	}
 }
+
+// Returns a Predicate that is true if all of fs are true (and in that case only, fs are evaluated in order)
+func And(fs ...Predicate) Predicate {
+	return func(msg any) bool {
+		for _, f := range fs {
+			if !f(msg) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Returns a Predicate that is true if one of fs is true (fs are evaluated in order until one is true)
+func Or(fs ...Predicate) Predicate {
+	return func(msg any) bool {
+		for _, f := range fs {
+			if f(msg) {
+				return true
+			}
+		}
+		return false
+	}
+}
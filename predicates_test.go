@@ -28,8 +28,8 @@ func TestPredicateEq(t *testing.T) {
 		}
 	})
 
-	t.Run("not(eq) creates a Predicate that checks non equality", func(t *testing.T) {
-		notEqualTo3 := not(eq(3))
+	t.Run("Not(eq) creates a Predicate that checks non equality", func(t *testing.T) {
+		notEqualTo3 := Not(eq(3))
 
 		if !notEqualTo3(4) {
 			t.Error("notEqualTo3: expected true but got false")
@@ -52,8 +52,8 @@ func TestPredicateContains(t *testing.T) {
 		}
 	})
 
-	t.Run("not(contain) creates a Predicate that checks non containing", func(t *testing.T) {
-		notContainWord := not(contain("word"))
+	t.Run("Not(contain) creates a Predicate that checks non containing", func(t *testing.T) {
+		notContainWord := Not(contain("word"))
 
 		if !notContainWord("mot") {
 			t.Error("notContainWord: expected true but got false")
@@ -87,8 +87,8 @@ func TestPredicateMatches(t *testing.T) {
 		}
 	})
 
-	t.Run("not(match) creates a Predicate that checks non matching", func(t *testing.T) {
-		notMatchDigits := not(match(digitsRE))
+	t.Run("Not(match) creates a Predicate that checks non matching", func(t *testing.T) {
+		notMatchDigits := Not(match(digitsRE))
 
 		if !notMatchDigits("abc") {
 			t.Error("notMatchDigits: expected true but got false")
@@ -98,3 +98,36 @@ func TestPredicateMatches(t *testing.T) {
 		}
 	})
 }
+
+func TestPredicateCombinators(t *testing.T) {
+	t.Run("And creates a Predicate that checks all predicates", func(t *testing.T) {
+		containsWordAndDigits := And(contain("word"), match(digitsRE))
+
+		if !containsWordAndDigits("word 42") {
+			t.Error("containsWordAndDigits: expected true but got false")
+		}
+		if containsWordAndDigits("word") {
+			t.Error("containsWordAndDigits: expected false but got true")
+		}
+	})
+
+	t.Run("Or creates a Predicate that checks one of predicates", func(t *testing.T) {
+		containsWordOrDigits := Or(contain("word"), match(digitsRE))
+
+		if !containsWordOrDigits("42") {
+			t.Error("containsWordOrDigits: expected true but got false")
+		}
+		if containsWordOrDigits("mot") {
+			t.Error("containsWordOrDigits: expected false but got true")
+		}
+	})
+
+	t.Run("And and Or have neutral values when empty", func(t *testing.T) {
+		if !And()("anything") {
+			t.Error("And(): expected true but got false")
+		}
+		if Or()("anything") {
+			t.Error("Or(): expected false but got true")
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package wsmock
+
+import "fmt"
+
+// Assertions are compiled into programs, run by assertion jobs over the message stream like a
+// nondeterministic automaton: several threads may be active at the same time (for instance one for
+// each branch of Either), each one waiting for its current condition to be done.
+type opcode int
+
+const (
+	opCondition opcode = iota // waits for condition c to be done
+	opSplit                   // continues both at x and y
+	opJump                    // continues at x
+	opMatch                   // the assertion succeeded
+)
+
+type instruction struct {
+	op    opcode
+	c     Condition
+	x, y  int
+	step  int    // index of the top-level condition in the assertion (used in logs)
+	label string // position inside a structural step (used in logs)
+	skip  bool   // messages passing the condition are not explained by it (see Recorder.Strict)
+}
+
+// Implemented by structural steps of assertions (like Either), that are compiled into several instructions
+// instead of being tried as conditions.
+type compilable interface {
+	compile(prog []instruction, step int) []instruction
+	name() string
+}
+
+// compiles the conditions of an assertion
+func compile(cs []Condition) []instruction {
+	var prog []instruction
+	for step, c := range cs {
+		prog = compileCondition(prog, c, step)
+	}
+	return append(prog, instruction{op: opMatch, step: len(cs)})
+}
+
+func compileCondition(prog []instruction, c Condition, step int) []instruction {
+	if w, ok := c.(*within); ok {
+		if cc, ok := w.Condition.(compilable); ok { // windows are not supported on structural steps
+			return append(prog, instruction{op: opCondition, c: unsupportedWindow(cc.name()), step: step})
+		}
+	}
+	if cc, ok := c.(compilable); ok {
+		return cc.compile(prog, step)
+	}
+	return append(prog, instruction{op: opCondition, c: c, step: step})
+}
+
+func compileSequence(prog []instruction, cs []Condition, step int) []instruction {
+	for _, c := range cs {
+		prog = compileCondition(prog, c, step)
+	}
+	return prog
+}
+
+// labels the instructions compiled from index start on, unless they already have a label (from a nested step)
+func labelFrom(prog []instruction, start int, label string) []instruction {
+	for i := start; i < len(prog); i++ {
+		if prog[i].label == "" {
+			prog[i].label = label
+		}
+	}
+	return prog
+}
+
+// replaces a structural step with a time window, so that the assertion fails instead of ignoring the window
+func unsupportedWindow(name string) Condition {
+	return ConditionFunc(func(_ bool, _ any, _ []any) (done, passed bool, err string) {
+		return true, false, "[Within] time windows are not supported on " + name + " steps"
+	})
+}
+
+// structural steps can't be tried as conditions (for instance in combinators)
+func structuralTry(name string) (done, passed bool, err string) {
+	return true, false, "[" + name + "] only supported in assertion chains"
+}
+
+// The either struct is a structural step: the assertion goes on if one of its branches (sequences of
+// conditions) passes.
+type either struct {
+	branches [][]Condition
+}
+
+func (c *either) Try(_ bool, _ any, _ []any) (done, passed bool, err string) {
+	return structuralTry("Either")
+}
+
+func (c *either) name() string {
+	return "Either"
+}
+
+// compiles to: split L1, next; L1: branch1; jump end; next: split L2, ... ; Ln: branchN; end
+func (c *either) compile(prog []instruction, step int) []instruction {
+	var jumps []int
+	for i, branch := range c.branches {
+		label := fmt.Sprintf("Either branch %v/%v", i+1, len(c.branches))
+		if i < len(c.branches)-1 {
+			split := len(prog)
+			prog = append(prog, instruction{op: opSplit, x: split + 1, step: step})
+			prog = labelFrom(compileSequence(prog, branch, step), split+1, label)
+			jumps = append(jumps, len(prog))
+			prog = append(prog, instruction{op: opJump, step: step})
+			prog[split].y = len(prog)
+		} else {
+			prog = labelFrom(compileSequence(prog, branch, step), len(prog), label)
+		}
+	}
+	for _, jump := range jumps {
+		prog[jump].x = len(prog)
+	}
+	return prog
+}
+
+// The optional struct is a structural step: the assertion goes on whether or not its condition passes.
+type optional struct {
+	c Condition
+}
+
+func (c *optional) Try(_ bool, _ any, _ []any) (done, passed bool, err string) {
+	return structuralTry("Optional")
+}
+
+func (c *optional) name() string {
+	return "Optional"
+}
+
+// compiles to: split L1, end; L1: condition; end
+func (c *optional) compile(prog []instruction, step int) []instruction {
+	split := len(prog)
+	prog = append(prog, instruction{op: opSplit, x: split + 1, step: step})
+	prog = labelFrom(compileCondition(prog, c.c, step), split+1, "Optional")
+	prog[split].y = len(prog)
+	return prog
+}
+
+// The repeat struct is a structural step: its condition has to pass n times in a row (each time with
+// a fresh instance).
+type repeat struct {
+	n int
+	c Condition
+}
+
+func (c *repeat) Try(_ bool, _ any, _ []any) (done, passed bool, err string) {
+	return structuralTry("Repeat")
+}
+
+func (c *repeat) name() string {
+	return "Repeat"
+}
+
+// compiles to n copies of the condition
+func (c *repeat) compile(prog []instruction, step int) []instruction {
+	for i := 0; i < c.n; i++ {
+		start := len(prog)
+		prog = compileCondition(prog, c.c, step)
+		prog = labelFrom(prog, start, fmt.Sprintf("Repeat %v/%v", i+1, c.n))
+	}
+	return prog
+}
+
+// compiles to: L: split L1, end; L1: NextToCheck(f); jump L; end
+func compileLoop(prog []instruction, f Predicate, step int, label string) []instruction {
+	split := len(prog)
+	prog = append(prog, instruction{op: opSplit, x: split + 1, step: step, label: label})
+	prog = append(prog, instruction{op: opCondition, c: NextToCheck(f), step: step, label: label})
+	prog = append(prog, instruction{op: opJump, x: split, step: step, label: label})
+	prog[split].y = len(prog)
+	return prog
+}
+
+// The zeroOrMore struct is a structural step: any number of next messages check its Predicate.
+type zeroOrMore struct {
+	f Predicate
+}
+
+func (c *zeroOrMore) Try(_ bool, _ any, _ []any) (done, passed bool, err string) {
+	return structuralTry("ZeroOrMore")
+}
+
+func (c *zeroOrMore) name() string {
+	return "ZeroOrMore"
+}
+
+func (c *zeroOrMore) compile(prog []instruction, step int) []instruction {
+	return compileLoop(prog, c.f, step, "ZeroOrMore")
+}
+
+// The oneOrMore struct is a structural step: at least one next message checks its Predicate.
+type oneOrMore struct {
+	f Predicate
+}
+
+func (c *oneOrMore) Try(_ bool, _ any, _ []any) (done, passed bool, err string) {
+	return structuralTry("OneOrMore")
+}
+
+func (c *oneOrMore) name() string {
+	return "OneOrMore"
+}
+
+// compiles to: NextToCheck(f); ZeroOrMore(f)
+func (c *oneOrMore) compile(prog []instruction, step int) []instruction {
+	prog = append(prog, instruction{op: opCondition, c: NextToCheck(c.f), step: step, label: "OneOrMore"})
+	return compileLoop(prog, c.f, step, "OneOrMore")
+}
+
+func anyMessage(_ any) bool {
+	return true
+}
+
+// The until struct is a structural step: any messages, then one that checks its Predicate. Contrary to OneToCheck,
+// all the messages checking the Predicate are candidates for the assertion to go on, not only the first one.
+type until struct {
+	f Predicate
+}
+
+func (c *until) Try(_ bool, _ any, _ []any) (done, passed bool, err string) {
+	return structuralTry("Until")
+}
+
+func (c *until) name() string {
+	return "Until"
+}
+
+// compiles to: L: split L1, L2; L1: NextToCheck(anyMessage); jump L; L2: NextToCheck(f)
+func (c *until) compile(prog []instruction, step int) []instruction {
+	split := len(prog)
+	prog = append(prog, instruction{op: opSplit, x: split + 1, step: step, label: "Until"})
+	prog = append(prog, instruction{op: opCondition, c: NextToCheck(anyMessage), step: step, label: "Until", skip: true})
+	prog = append(prog, instruction{op: opJump, x: split, step: step, label: "Until"})
+	prog[split].y = len(prog)
+	return append(prog, instruction{op: opCondition, c: NextToCheck(c.f), step: step, label: "Until"})
+}
@@ -0,0 +1,114 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompile(t *testing.T) {
+	t.Run("compiles a chain to sequential instructions", func(t *testing.T) {
+		prog := compile([]Condition{OneToBe(1), OneToBe(2)})
+		if len(prog) != 3 || prog[2].op != opMatch || prog[2].step != 2 {
+			t.Errorf("unexpected program: %+v", prog)
+		}
+	})
+
+	t.Run("compiles Either to splits and jumps", func(t *testing.T) {
+		e := &either{[][]Condition{{OneToBe(1), OneToBe(2)}, {OneToBe(3)}}}
+		prog := compile([]Condition{e, OneToBe(4)})
+		// split 1,5; cond 1; cond 2; jump 5; cond 3; cond 4; match
+		if len(prog) != 7 {
+			t.Fatalf("unexpected program length: %+v", prog)
+		}
+		if prog[0].op != opSplit || prog[0].x != 1 || prog[0].y != 4 {
+			t.Errorf("unexpected split: %+v", prog[0])
+		}
+		if prog[3].op != opJump || prog[3].x != 5 {
+			t.Errorf("unexpected jump: %+v", prog[3])
+		}
+		if prog[5].step != 1 {
+			t.Errorf("condition after Either should be step 1, got: %v", prog[5].step)
+		}
+	})
+
+	t.Run("fails when a window is set on a structural step", func(t *testing.T) {
+		prog := compile([]Condition{newWithin(&repeat{2, OneToBe(1)}, time.Second)})
+		if len(prog) != 2 || prog[0].op != opCondition {
+			t.Fatalf("unexpected program: %+v", prog)
+		}
+		done, passed, err := prog[0].c.Try(false, nil, nil)
+		if !done || passed || !strings.Contains(err, "not supported on Repeat") {
+			t.Errorf("unexpected result: %v %v %v", done, passed, err)
+		}
+	})
+
+	t.Run("compiles Optional to a split", func(t *testing.T) {
+		prog := compile([]Condition{&optional{OneToBe(1)}})
+		if prog[0].op != opSplit || prog[0].x != 1 || prog[0].y != 2 || prog[2].op != opMatch {
+			t.Errorf("unexpected program: %+v", prog)
+		}
+	})
+
+	t.Run("fails when structural steps are tried as conditions", func(t *testing.T) {
+		done, passed, err := (&optional{OneToBe(1)}).Try(false, 1, []any{1})
+		if !done || passed || !strings.Contains(err, "only supported in assertion chains") {
+			t.Errorf("unexpected result: %v %v %v", done, passed, err)
+		}
+	})
+}
+
+func TestCompileRepetition(t *testing.T) {
+	t.Run("labels repeated conditions", func(t *testing.T) {
+		prog := compile([]Condition{&repeat{2, OneToBe(1)}})
+		if len(prog) != 3 || prog[0].label != "Repeat 1/2" || prog[1].label != "Repeat 2/2" {
+			t.Errorf("unexpected program: %+v", prog)
+		}
+	})
+
+	t.Run("compiles ZeroOrMore to a loop", func(t *testing.T) {
+		prog := compile([]Condition{&zeroOrMore{anyMessage}})
+		// split 1,3; cond; jump 0; match
+		if prog[0].op != opSplit || prog[0].y != 3 || prog[2].op != opJump || prog[2].x != 0 || prog[3].op != opMatch {
+			t.Errorf("unexpected program: %+v", prog)
+		}
+	})
+
+	t.Run("keeps labels of nested steps", func(t *testing.T) {
+		prog := compile([]Condition{&repeat{2, &optional{OneToBe(1)}}})
+		if prog[1].label != "Optional" {
+			t.Errorf("unexpected label: %v", prog[1].label)
+		}
+	})
+}
+
+func TestStepInfo(t *testing.T) {
+	t.Run("explains which repetition failed", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		go func() {
+			conn.WriteJSON("tick")
+			conn.WriteJSON("tock")
+		}()
+		rec.NewAssertion().Repeat(3, NextToBe("tick"))
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "Step: condition #1/1 (Repeat 2/3)") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+
+	t.Run("does not explain steps of single condition assertions", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		go conn.WriteJSON("tock")
+		rec.NewAssertion().NextToBe("tick")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) != 1 || strings.Contains(rec.errors[0], "Step:") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package wsmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// A Protocol maps the values of a discriminator field (the message kinds) to Go types, so that messages
+// written to a recorder (and sent to its conn) are decoded into concrete types (see Recorder.UseProtocol).
+//
+// For instance, with messages like `{"kind": "join_ack", "payload": {"room": "lobby"}}`:
+//
+//	p := wsmock.NewProtocol("kind").Payload("payload")
+//	wsmock.Register[JoinAck](p, "join_ack")
+type Protocol struct {
+	field         string // discriminator
+	payload       string // if set, field decoded into the kind type (instead of the whole message)
+	rejectUnknown bool
+	types         map[string]reflect.Type
+	kinds         map[reflect.Type]string
+}
+
+// Returns a Protocol whose message kinds are the values of the given JSON field
+func NewProtocol(field string) *Protocol {
+	return &Protocol{
+		field: field,
+		types: make(map[string]reflect.Type),
+		kinds: make(map[reflect.Type]string),
+	}
+}
+
+// Decodes the given JSON field of messages into the type of their kind, instead of the whole message
+func (p *Protocol) Payload(field string) *Protocol {
+	p.payload = field
+	return p
+}
+
+// Reports messages whose kind is not registered (or that have no kind) as failures, instead of keeping
+// them undecoded
+func (p *Protocol) RejectUnknown() *Protocol {
+	p.rejectUnknown = true
+	return p
+}
+
+// Registers T as the type of the messages of the given kind
+func Register[T any](p *Protocol, kind string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	p.types[kind] = t
+	p.kinds[t] = kind
+}
+
+// Returns the kind of a decoded message
+func (p *Protocol) kindOf(m any) (string, bool) {
+	kind, ok := p.kinds[reflect.TypeOf(m)]
+	return kind, ok
+}
+
+// Decodes m into the type of its kind: messages already decoded are kept, others are unmarshalled from their
+// JSON data (see jsonData). Messages of unknown kind are returned as is (with an error if the Protocol rejects them).
+func (p *Protocol) decode(m any) (any, error) {
+	if _, ok := p.kindOf(m); ok {
+		return m, nil
+	}
+	data, err := jsonData(m)
+	if err != nil {
+		return m, p.unknown(fmt.Sprintf("message can't be JSON-encoded: %v", err))
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return m, p.unknown("message is not a JSON object")
+	}
+	var kind string
+	if raw, ok := fields[p.field]; !ok || json.Unmarshal(raw, &kind) != nil {
+		return m, p.unknown(fmt.Sprintf("message has no %q field", p.field))
+	}
+	t, ok := p.types[kind]
+	if !ok {
+		return m, p.unknown(fmt.Sprintf("unknown kind %q", kind))
+	}
+	if p.payload != "" {
+		if data, ok = fields[p.payload]; !ok {
+			data = []byte("null")
+		}
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return m, fmt.Errorf("[Protocol] message of kind %q can't be decoded into %v: %v", kind, t, err)
+	}
+	return v.Elem().Interface(), nil
+}
+
+func (p *Protocol) unknown(reason string) error {
+	if p.rejectUnknown {
+		return fmt.Errorf("[Protocol] %v", reason)
+	}
+	return nil
+}
+
+func (r *Recorder) getProtocol() *Protocol {
+	if r.parent != nil { // sub-recorders share the protocol of their conn
+		return r.parent.getProtocol()
+	}
+	return r.protocol.Load()
+}
+
+// decodes m with the recorder protocol if any, decoding errors being reported as failures
+func (r *Recorder) decode(m any) any {
+	p := r.getProtocol()
+	if p == nil {
+		return m
+	}
+	decoded, err := p.decode(m)
+	if err != nil {
+		r.addError(fmt.Sprintf("\nIn %v → %v\nFailing message (of type %T): %#v\n", r.name, err, m, m))
+	}
+	return decoded
+}
+
+// formats a message in logs, prefixed by its kind if the recorder has a protocol
+func (r *Recorder) format(m any) string {
+	if p := r.getProtocol(); p != nil {
+		if kind, ok := p.kindOf(m); ok {
+			return fmt.Sprintf("%v: %#v", kind, m)
+		}
+	}
+	return fmt.Sprintf("%#v", m)
+}
+
+// returns a Predicate checking that messages have type T and check f (if not nil)
+func kind[T any](f func(T) bool) Predicate {
+	return func(msg any) bool {
+		v, ok := msg.(T)
+		return ok && (f == nil || f(v))
+	}
+}
+
+// describes the kind T, and the predicate f if any
+func kindLabel[T any](f func(T) bool) string {
+	label := reflect.TypeOf((*T)(nil)).Elem().String()
+	if f != nil {
+		label += " checking predicate: " + runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	}
+	return label
+}
+
+// API
+
+// Sets the Protocol used to decode the messages written to the recorder (and sent to its conn), and to print
+// their kind in logs
+func (r *Recorder) UseProtocol(p *Protocol) {
+	r.protocol.Store(p)
+}
+
+// Returns a condition that succeeds if a new message has type T and checks f (if not nil)
+func OneToBeKind[T any](f func(T) bool) Condition {
+	return newOneTo(kind(f), "[OneToBeKind] no message is of kind "+kindLabel(f))
+}
+
+// Returns a condition that succeeds if the next message has type T and checks f (if not nil)
+func NextToBeKind[T any](f func(T) bool) Condition {
+	return newNextTo(kind(f), "[NextToBeKind] next message is not of kind "+kindLabel(f))
+}
+
+// Returns a condition that succeeds if the last message has type T and checks f (if not nil)
+func LastToBeKind[T any](f func(T) bool) Condition {
+	return newLastTo(kind(f), "[LastToBeKind] last message is not of kind "+kindLabel(f))
+}
+
+// Returns a condition that succeeds if all remaining messages have type T and check f (if not nil)
+func AllToBeKind[T any](f func(T) bool) Condition {
+	return newAllTo(kind(f), "[AllToBeKind] message is not of kind "+kindLabel(f))
+}
+
+// Returns a condition that succeeds if no remaining message has type T and checks f (if not nil)
+func NoneToBeKind[T any](f func(T) bool) Condition {
+	return newNoneTo(kind(f), "[NoneToBeKind] message is unexpectedly of kind "+kindLabel(f))
+}
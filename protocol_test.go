@@ -0,0 +1,82 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type joinAck struct {
+	Room string `json:"room"`
+}
+
+type chat struct {
+	Text string `json:"text"`
+}
+
+func newTestProtocol() *Protocol {
+	p := NewProtocol("kind").Payload("payload")
+	Register[joinAck](p, "join_ack")
+	Register[chat](p, "chat")
+	return p
+}
+
+func TestProtocolDecode(t *testing.T) {
+	p := newTestProtocol()
+	cases := []struct {
+		m    any
+		want any
+	}{
+		{joinAck{"lobby"}, joinAck{"lobby"}},
+		{`{"kind":"join_ack","payload":{"room":"lobby"}}`, joinAck{"lobby"}},
+		{[]byte(`{"kind":"chat","payload":{"text":"hi"}}`), chat{"hi"}},
+		{map[string]any{"kind": "chat", "payload": map[string]any{"text": "hi"}}, chat{"hi"}},
+		{`{"kind":"other"}`, `{"kind":"other"}`}, // unknown kinds are kept
+		{"pong", "pong"},
+	}
+	for _, c := range cases {
+		got, err := p.decode(c.m)
+		if err != nil || got != c.want {
+			t.Errorf("decode(%#v) = %#v, %v", c.m, got, err)
+		}
+	}
+
+	if _, err := p.decode(`{"kind":"chat","payload":{"text":1}}`); err == nil || !strings.Contains(err.Error(), `message of kind "chat" can't be decoded`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	p.RejectUnknown()
+	if _, err := p.decode(`{"kind":"other"}`); err == nil || !strings.Contains(err.Error(), `unknown kind "other"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := p.decode("pong"); err == nil || !strings.Contains(err.Error(), "not a JSON object") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestProtocolOutput(t *testing.T) {
+	t.Run("prints kind names", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.UseProtocol(newTestProtocol())
+
+		go conn.WriteJSON(map[string]any{"kind": "chat", "payload": map[string]any{"text": "hi"}})
+		rec.NewAssertion().WithCondition(NextToBeKind[joinAck](nil))
+		rec.RunAssertions(20 * time.Millisecond)
+
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], `chat: wsmock.chat{Text:"hi"}`) || !strings.Contains(rec.errors[0], "next message is not of kind wsmock.joinAck") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+
+	t.Run("reports unknown kinds", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.UseProtocol(newTestProtocol().RejectUnknown())
+
+		conn.WriteJSON(map[string]any{"kind": "other"})
+
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], `In recorder#0 → [Protocol] unknown kind "other"`) {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+}
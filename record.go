@@ -0,0 +1,57 @@
+package wsmock
+
+import "time"
+
+// A Record is a message along with the time it was recorded at: either written by the WebSocket server
+// handler to a recorded conn, or sent to the handler with GorillaConn.Send.
+type Record struct {
+	Message any
+	Time    time.Time
+}
+
+// returns the messages of the given records
+func messagesOf(records []Record) []any {
+	messages := make([]any, len(records))
+	for i, r := range records {
+		messages[i] = r.Message
+	}
+	return messages
+}
+
+// formats the time elapsed since the given reference
+func relativeTime(t, since time.Time) string {
+	return "+" + t.Sub(since).Round(time.Microsecond).String()
+}
+
+// The history a condition is tried on: written messages (with or without timestamps) and
+// messages sent to the conn.
+type history struct {
+	writes  []any
+	records []Record
+	sends   func() []Record
+}
+
+// returns a history made of a single message, timestamped now
+func historyOf(latest any) *history {
+	return &history{
+		writes:  []any{latest},
+		records: []Record{{latest, time.Now()}},
+		sends:   func() []Record { return nil },
+	}
+}
+
+func (h *history) latest() (Record, bool) {
+	return last(h.records)
+}
+
+// Tries condition c on the history, dispatching to timed conditions when needed
+func (h *history) try(c Condition, end bool) (done, passed bool, err string) {
+	if w, ok := c.(*within); ok {
+		c = w.Condition
+	}
+	if tc, ok := c.(timedCondition); ok {
+		return tc.tryTimed(end, h)
+	}
+	latest, _ := last(h.writes)
+	return c.Try(end, latest, h.writes)
+}
@@ -1,7 +1,9 @@
 package wsmock
 
 import (
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -12,12 +14,45 @@ import (
 // Its API is used to define assertions about these messages.
 type Recorder struct {
 	t            *testing.T
-	index        int // used in logs
+	index        int    // used in logs
+	name         string // used in logs
+	clock        Clock
+	timeline     *timeline // shared by all the recorders of t
 	currentRound *round
+	strict       bool                     // see Strict
+	protocol     atomic.Pointer[Protocol] // see UseProtocol
+	codec        atomic.Pointer[Codec]    // see GorillaConn.UseCodec
 	// ws communication
-	writeCh chan any
-	done    bool
-	doneCh  chan struct{}
+	doneMu sync.Mutex
+	done   bool
+	doneCh chan struct{}
+	// round boundaries (see BeginRound and RoundPolicy)
+	roundMu  sync.Mutex
+	policy   RoundPolicy
+	open     bool     // writes belong to the current round
+	begun    bool     // a round has been begun since the previous RunAssertions
+	pending  []Record // written during the current round, not processed yet
+	between  []Record // written between rounds
+	notifyCh chan struct{}
+	// sub-recorders (see Channel and Sub)
+	subMu   sync.Mutex
+	keyFunc KeyFunc
+	subs    map[string]*Recorder
+	subKeys []string // creation order
+	parent  *Recorder
+	// session assertions, evaluated over all messages written since creation (see Session)
+	session   *round
+	historyMu sync.Mutex
+	history   []Record
+	// events of the conn, in order (see Transcript and MatchSnapshot)
+	eventsMu        sync.Mutex
+	events          []event
+	eventsLimit     int  // see SetTranscriptLimit
+	roundEvents     int  // index of the first event of the current round
+	keepRoundEvents bool // the current round has snapshots
+	// messages sent to the conn during the current round (with GorillaConn.Send)
+	sendMu sync.Mutex
+	sends  []Record
 	// when fails
 	mu     sync.RWMutex
 	errors []string
@@ -25,42 +60,92 @@ type Recorder struct {
 
 func newRecorder(t *testing.T) *Recorder {
 	r := Recorder{
-		t:       t,
-		writeCh: make(chan any, 512),
-		doneCh:  make(chan struct{}),
+		t:        t,
+		clock:    getClock(t),
+		doneCh:   make(chan struct{}),
+		open:     true,
+		begun:    true,
+		notifyCh: make(chan struct{}, 1),
+		// transcript
+		eventsLimit: defaultTranscriptLimit,
 	}
 	r.index = indexRecorder(t, &r)
+	r.timeline = getTimeline(t, r.clock)
+	r.name = fmt.Sprintf("recorder#%v", r.index)
+	r.session = newSessionRound(r.clock.Now())
 	r.resetRound()
 	return &r
 }
 
 func (r *Recorder) resetRound() {
-	r.currentRound = newRound()
+	r.currentRound = newRound(r.clock)
+	r.mu.RLock()
+	r.currentRound.errorsAt = len(r.errors)
+	r.mu.RUnlock()
+	r.sendMu.Lock()
+	r.sends = nil
+	r.sendMu.Unlock()
+	r.markRoundEvents()
+}
+
+// called when the server handler writes to the corresponding conn with WriteJSON
+func (r *Recorder) record(m any) {
+	r.recordFrame(jsonFrame, m)
+}
+
+// called when the server handler writes to the corresponding conn (frame being the message type)
+func (r *Recorder) recordFrame(frame string, m any) {
+	w := Record{r.decode(m), r.clock.Now()}
+	r.addEvent(writeEvent, frame, w)
+	seq := r.timeline.add(r, w)
+	r.addToHistory(w)
+	r.push(w)
+	r.route(w, seq)
+}
+
+// called when a message is sent to the corresponding conn
+func (r *Recorder) recordSend(frame string, m any) {
+	w := Record{r.decode(m), r.clock.Now()}
+	r.addEvent(sendEvent, frame, w)
+	r.sendMu.Lock()
+	defer r.sendMu.Unlock()
+
+	r.sends = append(r.sends, w)
+}
+
+func (r *Recorder) getSends() []Record {
+	if r.parent != nil { // sub-recorders share the sends of their conn
+		return r.parent.getSends()
+	}
+	r.sendMu.Lock()
+	defer r.sendMu.Unlock()
+
+	return append([]Record(nil), r.sends...)
 }
 
 // called when corresponding conn is closed
 func (r *Recorder) stop() {
+	r.doneMu.Lock()
 	if !r.done {
 		r.done = true
 		close(r.doneCh)
 	}
+	r.doneMu.Unlock()
+	for _, sub := range r.getSubs() {
+		sub.stop()
+	}
 }
 
-// forward to assertionJobs
-func (r *Recorder) forwardWritesDuringRound() {
-	for {
-		select {
-		case w := <-r.writeCh:
-			for job := range r.currentRound.jobIndex {
-				if !job.done { // to prevent blocking channel
-					job.writeCh <- w
-				}
-			}
-		case <-r.doneCh:
-			// stop forwarding when round ends, serverWriteCh buffers new messages waiting for next round
-			return
-		}
-	}
+func (r *Recorder) isDone() bool {
+	r.doneMu.Lock()
+	defer r.doneMu.Unlock()
+
+	return r.done
+}
+
+// a recorder is idle when running its assertions has nothing to do: it then does not start any goroutine
+func (r *Recorder) idle() bool {
+	return len(r.currentRound.jobs) == 0 && len(r.currentRound.snapshots) == 0 && len(r.getSubs()) == 0 && !r.strict
 }
 
 func (r *Recorder) addError(err string) {
@@ -129,35 +214,59 @@ func (r *Recorder) NewAssertion() *Assertion {
 // - the conn is closed
 // - (or if there is no assertion on the recorder)
 //
+// (except in strict mode, where the timeout is always reached unless the conn is closed, see Strict)
+//
 // For instance some conditions (like NoneToBe) always need to wait until the timeout is reached
 // to succeed, but may fail sooner.
 //
 // At the end of RunAssertions, the recorder message history is flushed and assertions
 // are removed. It's then possible to add new assertions and run them with a fresh history
-// on the same recorder.
+// on the same recorder. Messages written between two RunAssertions are handled according
+// to the RoundPolicy of the recorder (see SetRoundPolicy and BeginRound).
 func (r *Recorder) RunAssertions(timeout time.Duration) {
 	r.t.Helper()
 
+	r.openRound(true)
+	if r.idle() {
+		r.endRound(true)
+		r.manageErrors()
+		r.resetRound()
+		return
+	}
 	// start
-	go r.forwardWritesDuringRound()
-	r.currentRound.start(timeout)
+	subsWg := r.runSubs(timeout)
+	r.currentRound.run(r, timeout, r.strict || len(r.currentRound.snapshots) > 0)
 	// wait
-	r.currentRound.wg.Wait()
+	subsWg.Wait()
+	if r.strict {
+		r.checkStrict()
+	}
+	r.checkSnapshots()
 	// manage potential assert errors
 	r.manageErrors()
 	// stop and reset round
+	r.endRound(false)
 	r.resetRound()
 }
 
 // Runs and waits for the outcome of all the assertions added to all the recorders
-// of this T test.
+// of this T test, then runs timeline assertions (see Timeline).
 func RunAssertions(t *testing.T, timeout time.Duration) {
 	t.Helper()
 
-	recs := getIndexedRecorders(t)
-	wg := sync.WaitGroup{}
+	timelineEnd := startTimeline(t, timeout)
+	runRecorders(getIndexedRecorders(t), timeout)
+	runTimeline(t, timelineEnd)
+}
 
+// runs the assertions of recorders concurrently (idle recorders don't need a goroutine)
+func runRecorders(recs []*Recorder, timeout time.Duration) {
+	wg := sync.WaitGroup{}
 	for _, r := range recs {
+		if r.idle() {
+			r.RunAssertions(timeout)
+			continue
+		}
 		wg.Add(1)
 		go func(r *Recorder) {
 			r.t.Helper()
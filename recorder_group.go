@@ -0,0 +1,185 @@
+package wsmock
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A RecorderGroup shares assertions across several recorders, for instance the recorders of the members of a chat room
+// receiving the same broadcasts. Failures are reported once for the whole group, listing which recorders failed.
+//
+// Group assertions are reported when all the recorders of the group ran them: if only some did, the group assertion
+// fails when the test is over.
+type RecorderGroup struct {
+	mu   sync.Mutex
+	recs []*Recorder
+	// number of group assertions (used in logs)
+	count int
+}
+
+// Each group assertion runs one assertion job per recorder, and is reported when all jobs are done
+type groupAssertion struct {
+	g       *RecorderGroup
+	index   int  // used in logs
+	negated bool // see RecorderGroup.None
+	// state
+	mu       sync.Mutex
+	jobs     []*assertionJob
+	pending  int
+	reported bool
+}
+
+// Initializes a RecorderGroup with the given recorders
+func NewRecorderGroup(recs ...*Recorder) *RecorderGroup {
+	return &RecorderGroup{recs: recs}
+}
+
+// Adds recorders to the group (assertions already created with Each or None don't apply to them)
+func (g *RecorderGroup) Add(recs ...*Recorder) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.recs = append(g.recs, recs...)
+}
+
+// Returns the recorders of the group
+func (g *RecorderGroup) Recorders() []*Recorder {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]*Recorder(nil), g.recs...)
+}
+
+func (g *RecorderGroup) newAssertion(negated bool) *Assertion {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	a := &Assertion{}
+	ga := &groupAssertion{g: g, index: g.count, negated: negated, pending: len(g.recs)}
+	g.count++
+	for _, r := range g.recs {
+		j := newAssertionJob(r, a)
+		j.group = ga
+		ga.jobs = append(ga.jobs, j)
+	}
+	return a
+}
+
+// Initializes a chainable Assertion that has to succeed on each recorder of the group.
+//
+// For instance `group.Each().OneToBe(m).NoneToBe(m)` checks that each recorder receives m exactly once.
+func (g *RecorderGroup) Each() *Assertion {
+	return g.newAssertion(false)
+}
+
+// Initializes a chainable Assertion that has to fail on each recorder of the group.
+//
+// For instance `group.None().OneToBe(m)` checks that no recorder receives m.
+func (g *RecorderGroup) None() *Assertion {
+	return g.newAssertion(true)
+}
+
+// Runs all the assertions added to the recorders of the group (see Recorder.RunAssertions)
+func (g *RecorderGroup) RunAssertions(timeout time.Duration) {
+	runRecorders(g.Recorders(), timeout)
+}
+
+// called when one of the jobs of the group assertion is done
+func (ga *groupAssertion) jobDone(j *assertionJob) {
+	ga.mu.Lock()
+	ga.pending--
+	last := ga.pending == 0
+	ga.reported = last
+	ga.mu.Unlock()
+
+	if last { // j's recorder has not reported its errors yet
+		ga.report(j.rec)
+	}
+}
+
+// returns the names and outputs of failing recorders (in the order of the group), among those whose job is done
+func (ga *groupAssertion) failures() (names, outputs []string) {
+	for _, j := range ga.jobs {
+		if !j.done {
+			continue
+		}
+		var output string
+		if ga.negated && j.passed {
+			output = j.output("[None] assertion unexpectedly passed", j.event)
+		} else if !ga.negated && !j.passed {
+			output = strings.Join(j.errors, "")
+		} else {
+			continue
+		}
+		names = append(names, j.rec.name)
+		outputs = append(outputs, output)
+	}
+	return
+}
+
+func (ga *groupAssertion) label() string {
+	if ga.negated {
+		return "None"
+	}
+	return "Each"
+}
+
+// reports failing recorders on rec
+func (ga *groupAssertion) report(rec *Recorder) {
+	names, outputs := ga.failures()
+	if len(names) == 0 {
+		return
+	}
+	intro := fmt.Sprintf(
+		"\nIn group → %v assertion#%v, %v/%v recorder(s) failed: %v\n",
+		ga.label(), ga.index, len(names), len(ga.jobs), strings.Join(names, ", "),
+	)
+	rec.addError(intro + strings.Join(outputs, ""))
+}
+
+// reports the group assertion on t if only some of its recorders ran their assertions, since the group report
+// is otherwise never emitted (called when the test is over)
+func (ga *groupAssertion) reportPending(t *testing.T) {
+	t.Helper()
+
+	ga.mu.Lock()
+	if ga.reported || ga.pending == 0 || ga.pending == len(ga.jobs) {
+		ga.mu.Unlock()
+		return
+	}
+	ga.reported = true
+	ga.mu.Unlock()
+
+	var pending []string
+	for _, j := range ga.jobs {
+		if !j.done {
+			pending = append(pending, j.rec.name)
+		}
+	}
+	names, outputs := ga.failures()
+	output := fmt.Sprintf(
+		"\nIn group → %v assertion#%v, %v/%v recorder(s) did not run their assertions: %v\n",
+		ga.label(), ga.index, len(pending), len(ga.jobs), strings.Join(pending, ", "),
+	)
+	if len(names) > 0 {
+		output += fmt.Sprintf("%v/%v recorder(s) failed: %v\n", len(names), len(ga.jobs), strings.Join(names, ", ")) + strings.Join(outputs, "")
+	}
+	t.Error(output)
+}
+
+// reports group assertions left pending by r and its sub-recorders (see groupAssertion.reportPending)
+func (r *Recorder) checkGroups() {
+	r.t.Helper()
+
+	for _, j := range r.currentRound.jobs {
+		if j.group != nil {
+			j.group.reportPending(r.t)
+		}
+	}
+	for _, sub := range r.getSubs() {
+		sub.checkGroups()
+	}
+}
@@ -0,0 +1,83 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderGroupReport(t *testing.T) {
+	t.Run("aggregates failures in a single report", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn0, rec0 := NewGorillaMockAndRecorder(mockT)
+		_, rec1 := NewGorillaMockAndRecorder(mockT)
+		_, rec2 := NewGorillaMockAndRecorder(mockT)
+		g := NewRecorderGroup(rec0, rec1, rec2)
+
+		go conn0.WriteJSON("broadcast")
+		g.Each().OneToBe("broadcast")
+		g.RunAssertions(50 * time.Millisecond)
+
+		errors := append(append(rec0.errors, rec1.errors...), rec2.errors...)
+		if len(errors) != 1 {
+			t.Fatalf("failures should be reported once, got: %v", errors)
+		}
+		if !strings.Contains(errors[0], "In group → Each assertion#0, 2/3 recorder(s) failed: recorder#1, recorder#2") {
+			t.Errorf("report should list failing recorders, got: %v", errors[0])
+		}
+		if !strings.Contains(errors[0], "In recorder#1 → assertion#0") || strings.Contains(errors[0], "In recorder#0") {
+			t.Errorf("report should detail failing recorders only, got: %v", errors[0])
+		}
+	})
+
+	t.Run("reports recorders where None assertions passed", func(t *testing.T) {
+		mockT := &testing.T{}
+		_, rec0 := NewGorillaMockAndRecorder(mockT)
+		conn1, rec1 := NewGorillaMockAndRecorder(mockT)
+		g := NewRecorderGroup(rec0, rec1)
+
+		go conn1.WriteJSON("broadcast")
+		g.None().OneToBe("broadcast")
+		g.RunAssertions(50 * time.Millisecond)
+
+		errors := append(rec0.errors, rec1.errors...)
+		if len(errors) != 1 || !strings.Contains(errors[0], "1/2 recorder(s) failed: recorder#1") || !strings.Contains(errors[0], "[None] assertion unexpectedly passed") {
+			t.Errorf("unexpected errors: %v", errors)
+		}
+	})
+
+	t.Run("reports failures when only some recorders ran their assertions", func(t *testing.T) {
+		mockT := &testing.T{}
+		_, rec0 := NewGorillaMockAndRecorder(mockT)
+		_, rec1 := NewGorillaMockAndRecorder(mockT)
+		g := NewRecorderGroup(rec0, rec1)
+
+		g.Each().OneToBe("broadcast")
+		rec0.RunAssertions(50 * time.Millisecond)
+		if len(rec0.errors) != 0 || mockT.Failed() {
+			t.Fatalf("group should not be reported before all recorders ran: %v", rec0.errors)
+		}
+
+		checkGroups(mockT)
+		if !mockT.Failed() {
+			t.Error("pending group assertion should be reported on cleanup")
+		}
+	})
+
+	t.Run("does not report pending group assertions twice", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn0, rec0 := NewGorillaMockAndRecorder(mockT)
+		conn1, rec1 := NewGorillaMockAndRecorder(mockT)
+		g := NewRecorderGroup(rec0, rec1)
+
+		go conn0.WriteJSON("broadcast")
+		go conn1.WriteJSON("broadcast")
+		g.Each().OneToBe("broadcast")
+		g.RunAssertions(50 * time.Millisecond)
+
+		checkGroups(mockT)
+		if mockT.Failed() {
+			t.Errorf("group assertion that ran on all recorders should not be reported on cleanup")
+		}
+	})
+}
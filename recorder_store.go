@@ -5,12 +5,22 @@ import (
 	"testing"
 )
 
-var store recorderStore = recorderStore{sync.RWMutex{}, make(map[*testing.T][]*Recorder)}
+var store recorderStore = recorderStore{
+	sync.RWMutex{},
+	make(map[*testing.T][]*Recorder),
+	make(map[*testing.T]Clock),
+	make(map[*testing.T]*timeline),
+	make(map[*testing.T][]string),
+}
 
-// used to find all recorders declared on a given testing.T
+// used to find all recorders declared on a given testing.T, the Clock they use, the timeline
+// of the messages written to them and where to save their transcript (see SaveTranscriptOnFailure)
 type recorderStore struct {
-	mu    sync.RWMutex
-	index map[*testing.T][]*Recorder
+	mu          sync.RWMutex
+	index       map[*testing.T][]*Recorder
+	clocks      map[*testing.T]Clock
+	timelines   map[*testing.T]*timeline
+	transcripts map[*testing.T][]string
 }
 
 // returns the index/position of recorder for the given *testing.T test
@@ -24,7 +34,7 @@ func indexRecorder(t *testing.T, r *Recorder) (index int) {
 
 	if length == 0 { // do it once
 		t.Cleanup(func() {
-			unindexRecorders(t)
+			cleanupRecorders(t)
 		})
 	}
 
@@ -42,6 +52,34 @@ func getIndexedRecorders(t *testing.T) (recorders []*Recorder) {
 	return store.index[t]
 }
 
+// called when t is over: checks that need the whole test are run before recorders are forgotten
+func cleanupRecorders(t *testing.T) {
+	t.Helper()
+
+	checkSessions(t)
+	checkGroups(t)
+	saveTranscripts(t)
+	unindexRecorders(t)
+}
+
+// evaluates the session assertions of the recorders of t (see Recorder.Session)
+func checkSessions(t *testing.T) {
+	t.Helper()
+
+	for _, r := range getIndexedRecorders(t) {
+		r.checkSession()
+	}
+}
+
+// reports the group assertions that only some of the recorders of t ran (see RecorderGroup)
+func checkGroups(t *testing.T) {
+	t.Helper()
+
+	for _, r := range getIndexedRecorders(t) {
+		r.checkGroups()
+	}
+}
+
 func unindexRecorders(t *testing.T) {
 	t.Helper()
 
@@ -49,4 +87,39 @@ func unindexRecorders(t *testing.T) {
 	defer store.mu.Unlock()
 
 	delete(store.index, t)
+	delete(store.timelines, t)
+}
+
+// returns the Clock set on t with UseClock, or the real clock
+func getClock(t *testing.T) Clock {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	if c, ok := store.clocks[t]; ok {
+		return c
+	}
+	return realClock{}
+}
+
+// returns the timeline of t, creating it if needed
+func getTimeline(t *testing.T, clock Clock) *timeline {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	tl, ok := store.timelines[t]
+	if !ok {
+		tl = newTimeline(clock.Now())
+		store.timelines[t] = tl
+	}
+	return tl
+}
+
+func unindexClock(t *testing.T) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if c, ok := store.clocks[t].(*FakeClock); ok {
+		c.StopAutoAdvance()
+	}
+	delete(store.clocks, t)
 }
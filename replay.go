@@ -0,0 +1,250 @@
+package wsmock
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+type replayConfig struct {
+	speed  float64 // 0 means no delay between sent messages
+	grace  time.Duration
+	masks  []string
+	paths  []jsonPath // parsed masks
+	ignore Predicate
+	decode bool // sends JSON text messages decoded
+}
+
+func newReplayConfig(opts []ReplayOption) (*replayConfig, error) {
+	c := &replayConfig{speed: 1, grace: 500 * time.Millisecond}
+	for _, opt := range opts {
+		opt(c)
+	}
+	for _, expr := range c.masks {
+		p, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		c.paths = append(c.paths, p)
+	}
+	return c, nil
+}
+
+// adds an assertion to rec expecting the messages written on conn in transcript, in order, and no other message
+func (c *replayConfig) expectWrites(rec *Recorder, transcript TranscriptEvents, conn string) *Assertion {
+	a := rec.NewAssertion()
+	if c.ignore != nil {
+		a.Filter(Not(c.ignore))
+	}
+	for _, e := range transcript {
+		if e.Conn != conn || e.Event != writeEvent || !isDataFrame(e.Type) {
+			continue
+		}
+		m := replayMessage(e)
+		if c.ignore != nil && c.ignore(m) {
+			continue
+		}
+		a.WithCondition(nextToReplay(m, c.paths))
+	}
+	return a.WithCondition(newNoneTo(func(any) bool { return true }, "[Replay] unexpected message after recorded messages"))
+}
+
+// A ReplayOption configures Replay
+type ReplayOption func(c *replayConfig)
+
+// Replays sent messages faster (factor > 1) or slower (factor < 1) than recorded
+func WithSpeed(factor float64) ReplayOption {
+	return func(c *replayConfig) {
+		c.speed = factor
+	}
+}
+
+// Replays sent messages without waiting between them
+func WithCompressedTiming() ReplayOption {
+	return func(c *replayConfig) {
+		c.speed = 0
+	}
+}
+
+// Sets how long the handler writes are waited for after the replayed duration (default is 500ms)
+func WithGrace(d time.Duration) ReplayOption {
+	return func(c *replayConfig) {
+		c.grace = d
+	}
+}
+
+// Ignores the fields selected by the JSONPath expressions (see Recorder.MatchSnapshot) when comparing handler
+// writes to recorded messages
+func WithIgnoredFields(paths ...string) ReplayOption {
+	return func(c *replayConfig) {
+		c.masks = append(c.masks, paths...)
+	}
+}
+
+// Ignores the handler writes and the recorded messages checking f (recorded messages are decoded from JSON)
+func WithIgnoredMessages(f Predicate) ReplayOption {
+	return func(c *replayConfig) {
+		if c.ignore == nil {
+			c.ignore = f
+		} else {
+			c.ignore = Or(c.ignore, f)
+		}
+	}
+}
+
+// Sends the recorded text messages holding a JSON object or array as decoded values (instead of strings), so that
+// handlers can read them with ReadJSON
+func WithDecodedJSONText() ReplayOption {
+	return func(c *replayConfig) {
+		c.decode = true
+	}
+}
+
+// returns the message to send again for a transcript event (see WithDecodedJSONText)
+func (c *replayConfig) sentMessage(e TranscriptEvent) any {
+	m := replayMessage(e)
+	if s, ok := m.(string); ok && c.decode && e.Type == textFrame {
+		if v, ok := jsonText(s); ok {
+			return v
+		}
+	}
+	return m
+}
+
+// returns the message of a transcript event, binary messages being decoded from base64 if needed
+// (that's how they are JSON-encoded)
+func replayMessage(e TranscriptEvent) any {
+	if s, ok := e.Message.(string); ok && e.Type == binaryFrame {
+		if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+			return b
+		}
+	}
+	return e.Message
+}
+
+func isDataFrame(frame string) bool {
+	return frame == jsonFrame || frame == textFrame || frame == binaryFrame
+}
+
+// returns a condition that succeeds if the next message is equal to the recorded one, once both are formatted
+// like in snapshots (and masked)
+func nextToReplay(expected any, masks []jsonPath) Condition {
+	formatted := formatContent(expected, masks)
+	return newNextTo(func(msg any) bool {
+		return formatContent(msg, masks) == formatted
+	}, "[Replay] next message does not match recorded message: "+formatted)
+}
+
+// API
+
+// Reads a transcript in the JSON Lines format (see TranscriptEvents.WriteJSONL)
+func ReadTranscript(r io.Reader) (TranscriptEvents, error) {
+	var ts TranscriptEvents
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e TranscriptEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("[wsmock] transcript line %v: %w", line, err)
+		}
+		ts = append(ts, e)
+	}
+	return ts, scanner.Err()
+}
+
+// Reads a transcript from a JSON Lines file (see ReadTranscript)
+func LoadTranscript(path string) (TranscriptEvents, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadTranscript(f)
+}
+
+// Replays a transcript (see Transcript and ReadTranscript) as scripted clients: a conn mock is created for each conn
+// of the transcript and served by handler, messages sent by clients are sent again with their recorded relative timing
+// (see WithSpeed, WithCompressedTiming and WithDecodedJSONText), and the handler writes are expected to match the recorded ones, in order
+// on each conn (see WithIgnoredFields and WithIgnoredMessages). Other events (reads, control frames, close) are
+// ignored.
+//
+// Replay waits for the replayed duration plus a grace period (see WithGrace), failing if the handler writes other
+// messages meanwhile, and then closes the conns.
+func Replay(t *testing.T, handler func(conn IGorilla), transcript TranscriptEvents, opts ...ReplayOption) {
+	t.Helper()
+
+	c, err := newReplayConfig(opts)
+	if err != nil {
+		t.Errorf("[Replay] %v", err)
+		return
+	}
+	if len(transcript) == 0 {
+		return
+	}
+
+	// conns in order of appearance
+	var names []string
+	index := make(map[string]int)
+	for _, e := range transcript {
+		if _, ok := index[e.Conn]; !ok {
+			index[e.Conn] = len(names)
+			names = append(names, e.Conn)
+		}
+	}
+	pool := NewGorillaPool(t, len(names), WithNames(func(i int) string { return names[i] }))
+	for i, rec := range pool.recs {
+		c.expectWrites(rec, transcript, names[i])
+	}
+	for _, conn := range pool.conns {
+		go handler(conn)
+	}
+
+	// script
+	clock := pool.recs[0].clock
+	start := transcript[0].Time
+	duration := time.Duration(0)
+	if c.speed > 0 {
+		duration = time.Duration(float64(transcript[len(transcript)-1].Time.Sub(start)) / c.speed)
+	}
+	stopCh := make(chan struct{})
+	scriptDone := make(chan struct{})
+	go func() { // stops when the round ends, even if the handler does not read anymore
+		defer close(scriptDone)
+		elapsed := time.Duration(0)
+		for _, e := range transcript {
+			if e.Event != sendEvent || !isDataFrame(e.Type) {
+				continue
+			}
+			if c.speed > 0 {
+				if at := time.Duration(float64(e.Time.Sub(start)) / c.speed); at > elapsed {
+					select {
+					case <-clock.After(at - elapsed):
+					case <-stopCh:
+						return
+					}
+					elapsed = at
+				}
+			}
+			if !pool.conns[index[e.Conn]].sendUntil(c.sentMessage(e), stopCh) {
+				return
+			}
+		}
+	}()
+
+	// assert
+	pool.RunAssertions(duration + c.grace)
+	close(stopCh)
+	<-scriptDone
+	pool.CloseAll()
+}
@@ -0,0 +1,57 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplayMessage(t *testing.T) {
+	t.Run("decodes base64 binary messages", func(t *testing.T) {
+		m := replayMessage(TranscriptEvent{Type: binaryFrame, Message: "AQI="})
+		if b, ok := m.([]byte); !ok || len(b) != 2 || b[0] != 1 || b[1] != 2 {
+			t.Errorf("unexpected message: %#v", m)
+		}
+	})
+
+	t.Run("keeps other messages", func(t *testing.T) {
+		if m := replayMessage(TranscriptEvent{Type: textFrame, Message: "AQI="}); m != "AQI=" {
+			t.Errorf("unexpected message: %#v", m)
+		}
+	})
+}
+
+func TestSentMessage(t *testing.T) {
+	t.Run("decodes JSON objects and arrays with WithDecodedJSONText", func(t *testing.T) {
+		c := &replayConfig{}
+		WithDecodedJSONText()(c)
+		if m, ok := c.sentMessage(TranscriptEvent{Type: textFrame, Message: `{"kind":"join"}`}).(map[string]any); !ok || m["kind"] != "join" {
+			t.Errorf("unexpected message: %#v", m)
+		}
+		if m := c.sentMessage(TranscriptEvent{Type: textFrame, Message: "42"}); m != "42" {
+			t.Errorf("unexpected message: %#v", m)
+		}
+	})
+
+	t.Run("keeps JSON text by default", func(t *testing.T) {
+		c := &replayConfig{}
+		if m := c.sentMessage(TranscriptEvent{Type: textFrame, Message: `{"kind":"join"}`}); m != `{"kind":"join"}` {
+			t.Errorf("unexpected message: %#v", m)
+		}
+	})
+}
+
+func TestReadTranscript(t *testing.T) {
+	t.Run("skips empty lines", func(t *testing.T) {
+		ts, err := ReadTranscript(strings.NewReader("\n" + `{"conn":"a","event":"send","type":"text","message":"hi"}` + "\n\n"))
+		if err != nil || len(ts) != 1 || ts[0].Conn != "a" {
+			t.Errorf("unexpected transcript: %#v, %v", ts, err)
+		}
+	})
+
+	t.Run("reports invalid lines", func(t *testing.T) {
+		_, err := ReadTranscript(strings.NewReader(`{"conn":"a"}` + "\nnot json\n"))
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -1,34 +1,135 @@
 package wsmock
 
 import (
-	"sync"
 	"time"
 )
 
+// A round runs the assertions of a recorder, from RunAssertions to the end of their evaluation.
+//
+// All the jobs of a round are run by a single goroutine: messages are appended to a log shared by the jobs
+// (each job keeping a view of the log up to the latest message it processed), and a single timer is
+// used for the earliest deadline of the jobs (timeout or time windows).
 type round struct {
-	wg       sync.WaitGroup // track if assertions are finished
-	jobIndex map[*assertionJob]bool
+	jobs      []*assertionJob
+	clock     Clock     // used by jobs for time windows
+	since     time.Time // used to print relative timestamps in logs
+	log       messageLog
+	session   bool       // see Recorder.Session
+	snapshots []snapshot // see Recorder.MatchSnapshot
+	errorsAt  int        // number of errors of the recorder before the round (see Recorder.Strict)
 }
 
-func newRound() *round {
-	return &round{
-		wg:       sync.WaitGroup{},
-		jobIndex: make(map[*assertionJob]bool),
+// The messageLog holds all messages written during a round. It is append-only, so that jobs can share it.
+type messageLog struct {
+	records  []Record
+	messages []any // same messages as records, without timestamps
+}
+
+func (l *messageLog) append(w Record) {
+	l.records = append(l.records, w)
+	l.messages = append(l.messages, w.Message)
+}
+
+func newRound(clock Clock) *round {
+	return &round{clock: clock, since: clock.Now()}
+}
+
+// used in logs
+func (r *round) label() string {
+	if r.session {
+		return "session assertion"
 	}
+	return "assertion"
 }
 
 func (r *round) addJob(j *assertionJob) (index int) {
-	index = len(r.jobIndex) // index is length before adding new job (or new length minus one)
-	r.jobIndex[j] = true
-	r.wg.Add(1)
+	index = len(r.jobs) // index is length before adding new job (or new length minus one)
+	r.jobs = append(r.jobs, j)
 	return
 }
 
-func (r *round) start(timeout time.Duration) {
-	for j := range r.jobIndex {
-		go func(j *assertionJob) {
-			defer r.wg.Done()
-			j.loopWithTimeout(timeout)
-		}(j)
+// calls f on active jobs and returns the ones that are not finished
+func (r *round) step(active []*assertionJob, f func(j *assertionJob) (finished bool)) []*assertionJob {
+	kept := active[:0]
+	for _, j := range active {
+		if f(j) {
+			r.jobDone(j)
+		} else {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
+
+func (r *round) jobDone(j *assertionJob) {
+	if j.group != nil {
+		j.group.jobDone(j)
+	}
+}
+
+// Runs the jobs until they are finished. If keepRecording is true (see Recorder.Strict), messages are
+// recorded until the timeout is reached or the conn is closed, even if jobs are already finished.
+func (r *round) run(rec *Recorder, timeout time.Duration, keepRecording bool) {
+	endAt := rec.clock.Now().Add(timeout)
+	active := make([]*assertionJob, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		if j.begin(endAt) {
+			r.jobDone(j)
+		} else {
+			active = append(active, j)
+		}
+	}
+
+	var timerCh <-chan time.Time
+	var timerAt time.Time
+	for len(active) > 0 || keepRecording {
+		if w, ok := rec.nextWrite(); ok {
+			r.log.append(w)
+			active = r.step(active, func(j *assertionJob) bool {
+				return j.onMessage(&r.log)
+			})
+			continue
+		}
+		earliest := endAt
+		for _, j := range active {
+			if d := j.deadline(); d.Before(earliest) {
+				earliest = d
+			}
+		}
+		if timerCh == nil || !earliest.Equal(timerAt) {
+			timerAt = earliest
+			timerCh = rec.clock.After(earliest.Sub(rec.clock.Now()))
+		}
+
+		select {
+		case <-rec.notifyCh: // a message has been written
+		case <-timerCh:
+			timerCh = nil
+			active = r.step(active, func(j *assertionJob) bool {
+				return j.onTimer(timerAt)
+			})
+			if !timerAt.Before(endAt) { // timeout is reached
+				return
+			}
+		case <-rec.doneCh: // conn is closed
+			r.end(rec, active, keepRecording)
+			return
+		}
+	}
+}
+
+// Processes the messages written before the conn was closed and finishes the active jobs. If keepRecording is
+// true, messages are recorded even if jobs are already finished.
+func (r *round) end(rec *Recorder, active []*assertionJob, keepRecording bool) {
+	for w, ok := rec.nextWrite(); ok && (len(active) > 0 || keepRecording); w, ok = rec.nextWrite() {
+		r.log.append(w)
+		active = r.step(active, func(j *assertionJob) bool {
+			return j.onMessage(&r.log)
+		})
 	}
+	r.step(active, func(j *assertionJob) bool {
+		j.event = "end"
+		j.finish("end", "")
+		return true
+	})
 }
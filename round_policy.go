@@ -0,0 +1,149 @@
+package wsmock
+
+import (
+	"fmt"
+)
+
+// A RoundPolicy defines what happens to messages written between rounds, that is after a round
+// ended (RunAssertions returned or EndRound was called) and before the next one begins (BeginRound
+// was called or RunAssertions is called).
+type RoundPolicy int
+
+const (
+	// Messages written between rounds are carried over to the next round, as if they were written
+	// when it begins (default policy).
+	CarryOver RoundPolicy = iota
+	// Messages written between rounds are discarded.
+	Drop
+	// Messages written between rounds make the next round fail.
+	Fail
+)
+
+func (p RoundPolicy) String() string {
+	switch p {
+	case Drop:
+		return "Drop"
+	case Fail:
+		return "Fail"
+	}
+	return "CarryOver"
+}
+
+// called when the server handler writes to the corresponding conn (or to the parent of a sub-recorder)
+func (r *Recorder) push(w Record) {
+	r.roundMu.Lock()
+	if r.open {
+		r.pending = append(r.pending, w)
+	} else if r.policy != Drop {
+		r.between = append(r.between, w)
+	}
+	r.roundMu.Unlock()
+
+	select { // wakes up the running round, if any
+	case r.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// pops the oldest message written during the current round and not processed yet
+func (r *Recorder) nextWrite() (w Record, ok bool) {
+	r.roundMu.Lock()
+	defer r.roundMu.Unlock()
+
+	if len(r.pending) == 0 {
+		return
+	}
+	w = r.pending[0]
+	r.pending = r.pending[1:]
+	return w, true
+}
+
+// opens the current round, messages written between rounds being handled according to the policy
+// (if implicit, the round is only opened if none has been begun since the previous RunAssertions)
+func (r *Recorder) openRound(implicit bool) {
+	r.roundMu.Lock()
+	defer r.roundMu.Unlock()
+
+	if r.open || (implicit && r.begun) {
+		return
+	}
+	r.begun = true
+	r.open = true
+	between := r.between
+	r.between = nil
+	switch r.policy {
+	case CarryOver:
+		r.pending = append(r.pending, between...)
+	case Fail:
+		if len(between) > 0 {
+			output := ""
+			for _, w := range between {
+				output += fmt.Sprintf("\tmessage [%v] %v\n", relativeTime(w.Time, r.currentRound.since), r.format(w.Message))
+			}
+			intro := fmt.Sprintf("\nIn %v → %v message(s) written between rounds:\n", r.name, len(between))
+			r.addError(intro + output)
+		}
+	}
+}
+
+// closes the current round: following messages are written between rounds
+func (r *Recorder) closeRound() {
+	r.roundMu.Lock()
+	defer r.roundMu.Unlock()
+
+	r.open = false
+}
+
+// called at the end of RunAssertions: messages of the round that have not been processed (because
+// assertions were finished before) are handled as if they were written between rounds, unless
+// the round consumes them (if it's idle, there is nothing to process them)
+func (r *Recorder) endRound(consume bool) {
+	r.roundMu.Lock()
+	defer r.roundMu.Unlock()
+
+	r.open = false
+	r.begun = false
+	if !consume && r.policy != Drop {
+		r.between = append(r.pending, r.between...)
+	}
+	r.pending = nil
+}
+
+// API
+
+// Sets the policy applied to messages written between rounds (default is CarryOver), for this
+// recorder and its sub-recorders.
+func (r *Recorder) SetRoundPolicy(p RoundPolicy) {
+	r.roundMu.Lock()
+	r.policy = p
+	r.roundMu.Unlock()
+
+	for _, sub := range r.getSubs() {
+		sub.SetRoundPolicy(p)
+	}
+}
+
+// Begins a new round: messages written from now on belong to the round evaluated by the next
+// RunAssertions, and messages written since the previous round are handled according to the
+// RoundPolicy (see SetRoundPolicy).
+//
+// Calling BeginRound is optional: a recorder is in a round when created, and RunAssertions begins
+// a round if none has been begun since the previous RunAssertions. It's useful to exclude messages
+// written before a script is started (with the Drop or Fail policies).
+func (r *Recorder) BeginRound() {
+	r.openRound(false)
+	for _, sub := range r.getSubs() {
+		sub.BeginRound()
+	}
+}
+
+// Ends the current round: messages written from now on are handled according to the RoundPolicy
+// (see SetRoundPolicy), and the next RunAssertions only evaluates messages written until EndRound.
+//
+// Calling EndRound is optional: the round otherwise ends when RunAssertions returns.
+func (r *Recorder) EndRound() {
+	r.closeRound()
+	for _, sub := range r.getSubs() {
+		sub.EndRound()
+	}
+}
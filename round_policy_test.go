@@ -0,0 +1,73 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundPolicy(t *testing.T) {
+	t.Run("routes writes depending on round boundaries and policy", func(t *testing.T) {
+		cases := []struct {
+			policy           RoundPolicy
+			pending, between int
+		}{
+			{CarryOver, 1, 1},
+			{Drop, 1, 0},
+			{Fail, 1, 1},
+		}
+		for _, c := range cases {
+			mockT := &testing.T{}
+			conn, rec := NewGorillaMockAndRecorder(mockT)
+			rec.SetRoundPolicy(c.policy)
+
+			conn.WriteJSON("in round")
+			rec.EndRound()
+			conn.WriteJSON("between rounds")
+
+			if len(rec.pending) != c.pending || len(rec.between) != c.between {
+				t.Errorf("%v: unexpected pending %v and between %v", c.policy, rec.pending, rec.between)
+			}
+		}
+	})
+
+	t.Run("carries over unprocessed messages of a round", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		conn.WriteJSON("a")
+		conn.WriteJSON("b")
+		rec.NewAssertion().NextToBe("a")
+		rec.RunAssertions(10 * time.Millisecond)
+
+		if len(rec.between) != 1 || rec.between[0].Message != "b" {
+			t.Errorf("unexpected carried over messages: %v", rec.between)
+		}
+	})
+
+	t.Run("idle rounds consume their messages", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		conn.WriteJSON("a")
+		rec.RunAssertions(10 * time.Millisecond)
+
+		if len(rec.pending) != 0 || len(rec.between) != 0 {
+			t.Errorf("unexpected messages left: %v %v", rec.pending, rec.between)
+		}
+	})
+
+	t.Run("lists messages written between rounds with Fail", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.SetRoundPolicy(Fail)
+		rec.RunAssertions(10 * time.Millisecond)
+
+		conn.WriteJSON("late")
+		rec.BeginRound()
+
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "1 message(s) written between rounds") || !strings.Contains(rec.errors[0], `"late"`) {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package wsmock
+
+import (
+	"time"
+)
+
+// A replayClock is set to the time of the messages replayed by a session round (see Recorder.Session)
+type replayClock struct {
+	at time.Time
+}
+
+func (c *replayClock) Now() time.Time {
+	return c.at
+}
+
+func (c *replayClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.at.Add(d)
+	return ch
+}
+
+func newSessionRound(since time.Time) *round {
+	r := newRound(&replayClock{since})
+	r.session = true
+	return r
+}
+
+// Runs the jobs over records as if they were written at their timestamps, deadlines (time windows)
+// being processed in between, until endAt.
+func (r *round) replay(records []Record, endAt time.Time) {
+	clock := r.clock.(*replayClock)
+	active := make([]*assertionJob, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		if j.begin(endAt) {
+			r.jobDone(j)
+		} else {
+			active = append(active, j)
+		}
+	}
+	for _, w := range records {
+		active = r.replayTimers(active, w.Time)
+		clock.at = w.Time
+		r.log.append(w)
+		active = r.step(active, func(j *assertionJob) bool {
+			return j.onMessage(&r.log)
+		})
+	}
+	active = r.replayTimers(active, endAt)
+	clock.at = endAt
+	r.step(active, func(j *assertionJob) bool {
+		return j.onTimer(endAt)
+	})
+}
+
+// processes the deadlines of active jobs that are before until
+func (r *round) replayTimers(active []*assertionJob, until time.Time) []*assertionJob {
+	clock := r.clock.(*replayClock)
+	for len(active) > 0 {
+		earliest := until
+		for _, j := range active {
+			if d := j.deadline(); d.Before(earliest) {
+				earliest = d
+			}
+		}
+		if !earliest.Before(until) {
+			return active
+		}
+		clock.at = earliest
+		active = r.step(active, func(j *assertionJob) bool {
+			return j.onTimer(earliest)
+		})
+	}
+	return active
+}
+
+// evaluates session assertions over the whole history, called when the test finishes
+func (r *Recorder) checkSession() {
+	r.t.Helper()
+
+	if len(r.session.jobs) > 0 {
+		r.mu.RLock()
+		count := len(r.errors)
+		r.mu.RUnlock()
+
+		r.session.replay(r.getHistory(), r.clock.Now())
+
+		r.mu.RLock()
+		for _, err := range r.errors[count:] {
+			r.outputError(err)
+		}
+		r.mu.RUnlock()
+	}
+	for _, sub := range r.getSubs() {
+		sub.checkSession()
+	}
+}
+
+// API
+
+// Initialize a new chainable Assertion evaluated over every message written to the recorder since
+// its creation (whereas the history of NewAssertion is flushed after each RunAssertions).
+//
+// Session assertions are checked when the test finishes (with t.Cleanup), time windows being
+// evaluated against the timestamps of the messages.
+func (r *Recorder) Session() *Assertion {
+	a := &Assertion{}
+	newRoundJob(r, r.session, a)
+	return a
+}
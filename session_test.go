@@ -0,0 +1,84 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSession(t *testing.T) {
+	t.Run("evaluates assertions over all rounds", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Session().NoneToBe("secret")
+
+		conn.WriteJSON("a")
+		rec.NewAssertion().OneToBe("a")
+		rec.RunAssertions(10 * time.Millisecond)
+		conn.WriteJSON("secret")
+		rec.NewAssertion().OneToBe("secret")
+		rec.RunAssertions(10 * time.Millisecond)
+
+		if mockT.Failed() {
+			t.Fatalf("rounds should succeed: %v", rec.errors)
+		}
+		checkSessions(mockT)
+		if !mockT.Failed() {
+			t.Fatal("session should fail")
+		}
+		err := rec.errors[len(rec.errors)-1]
+		if !strings.Contains(err, "session assertion#0, 2 messages received") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("succeeds when all messages check the assertion", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Session().OneToBe("a").OneToBe("b")
+
+		conn.WriteJSON("a")
+		rec.RunAssertions(10 * time.Millisecond)
+		conn.WriteJSON("b")
+		rec.RunAssertions(10 * time.Millisecond)
+		checkSessions(mockT)
+
+		if mockT.Failed() {
+			t.Errorf("session should succeed: %v", rec.errors)
+		}
+	})
+
+	t.Run("evaluates time windows against message timestamps", func(t *testing.T) {
+		clock := NewFakeClock()
+		mockT := &testing.T{}
+		UseClock(mockT, clock)
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Session().OneToBe("a").OneToBe("b").Within(time.Second)
+
+		conn.WriteJSON("a")
+		clock.Advance(2 * time.Second)
+		conn.WriteJSON("b")
+		checkSessions(mockT)
+
+		if !mockT.Failed() {
+			t.Error("session should fail since b is written after the time window")
+		}
+	})
+
+	t.Run("is evaluated on sub-recorders", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Channel(func(m any) (string, bool) { return m.(string)[:1], true })
+		rec.Sub("x").Session().AllToCheck(func(m any) bool { return strings.HasPrefix(m.(string), "x") })
+		rec.Sub("y").Session().NoneToBe("y2")
+
+		conn.WriteJSON("x1")
+		conn.WriteJSON("y1")
+		conn.WriteJSON("y2")
+		checkSessions(mockT)
+
+		if !mockT.Failed() {
+			t.Error("session of sub-recorder y should fail")
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package wsmock
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var updateSnapshots = flag.Bool("wsmock.update", false, "rewrite the golden files of wsmock snapshots (see Recorder.MatchSnapshot)")
+
+// value replacing masked fields in snapshots
+const maskedValue = "<masked>"
+
+// A snapshot compares the transcript of a round to a golden file (see MatchSnapshot)
+type snapshot struct {
+	t     *testing.T
+	path  string
+	masks []string // JSONPath expressions
+}
+
+// formats events one per line, with their kind, frame type and content (masked)
+func formatTranscript(events []event, masks []jsonPath) string {
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "%-5v %-6v %v\n", e.kind, e.frame, formatContent(e.Message, masks))
+	}
+	return b.String()
+}
+
+// formats a message as JSON when possible (JSON text messages included), so that masks can be applied
+func formatContent(m any, masks []jsonPath) string {
+	var v any
+	switch msg := m.(type) {
+	case []byte:
+		return hex.EncodeToString(msg)
+	case string:
+		var ok bool
+		if v, ok = jsonText(msg); !ok {
+			return marshalText(msg)
+		}
+	default:
+		b, ok := textOf(m)
+		if !ok || json.Unmarshal(b, &v) != nil {
+			return fmt.Sprintf("%#v", m)
+		}
+	}
+	for _, p := range masks {
+		v = p.mask(v)
+	}
+	return marshalText(v) // keys are sorted, making output stable
+}
+
+// decodes a text message holding a JSON object or array (other JSON values like "42" are considered as plain text)
+func jsonText(s string) (v any, ok bool) {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") || json.Unmarshal([]byte(trimmed), &v) != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// JSON-marshals v without escaping HTML characters (for readability)
+func marshalText(v any) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// A jsonPath is a parsed JSONPath expression, supporting `$`, `.name`, `['name']`, `[n]`, `[*]`, `.*` and `..name`
+type jsonPath []pathStep
+
+type pathStep struct {
+	name      string // field name, "*" for any field or element
+	index     int    // array index (if name is empty)
+	recursive bool   // `..` descendant step
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q should start with $", expr)
+	}
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		step := pathStep{}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			step.recursive = true
+			rest = rest[2:]
+			step.name, rest = readName(rest)
+		case strings.HasPrefix(rest, "."):
+			step.name, rest = readName(rest[1:])
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed bracket", expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if inner == "*" {
+				step.name = "*"
+			} else if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				step.name = inner[1 : len(inner)-1]
+			} else if i, err := strconv.Atoi(inner); err == nil && i >= 0 {
+				step.index = i
+			} else {
+				return nil, fmt.Errorf("JSONPath %q has an invalid bracket: [%v]", expr, inner)
+			}
+		default:
+			return nil, fmt.Errorf("JSONPath %q is invalid at: %v", expr, rest)
+		}
+		if step.recursive && step.name == "" {
+			return nil, fmt.Errorf("JSONPath %q should name a field after ..", expr)
+		}
+		path = append(path, step)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("JSONPath %q does not select a field", expr)
+	}
+	return path, nil
+}
+
+func readName(s string) (name, rest string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// returns v where the values selected by the path are replaced by maskedValue
+func (p jsonPath) mask(v any) any {
+	if len(p) == 0 {
+		return maskedValue
+	}
+	step, next := p[0], p[1:]
+	switch node := v.(type) {
+	case map[string]any:
+		for k, child := range node {
+			if step.name == "*" || step.name == k {
+				node[k] = next.mask(child)
+			} else if step.recursive {
+				node[k] = p.mask(child)
+			}
+		}
+	case []any:
+		for i, child := range node {
+			if step.name == "*" || (step.name == "" && step.index == i) {
+				node[i] = next.mask(child)
+			} else if step.recursive {
+				node[i] = p.mask(child)
+			}
+		}
+	}
+	return v
+}
+
+// returns a line diff between expected and got (LCS based), lines being prefixed by "-" (missing) or "+" (unexpected)
+func diffLines(expected, got string) string {
+	a := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("\t  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			out.WriteString("\t+ " + b[j] + "\n")
+			j++
+		default:
+			out.WriteString("\t- " + a[i] + "\n")
+			i++
+		}
+	}
+	return out.String()
+}
+
+// compares the transcript of the round to the golden files of the snapshots (or rewrites them with -wsmock.update)
+func (r *Recorder) checkSnapshots() {
+	snapshots := r.currentRound.snapshots
+	if len(snapshots) == 0 {
+		return
+	}
+	events := r.getRoundEvents()
+	for _, s := range snapshots {
+		s.t.Helper()
+		if err := s.check(events); err != nil {
+			s.t.Errorf("\nIn %v → snapshot %v: %v", r.name, s.path, err)
+		}
+	}
+}
+
+func (s snapshot) check(events []event) error {
+	var masks []jsonPath
+	for _, expr := range s.masks {
+		p, err := parseJSONPath(expr)
+		if err != nil {
+			return err
+		}
+		masks = append(masks, p)
+	}
+	got := formatTranscript(events, masks)
+	if *updateSnapshots {
+		if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(s.path, []byte(got), 0o644)
+	}
+	expected, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("golden file does not exist (run tests with -wsmock.update to create it)")
+	} else if err != nil {
+		return err
+	}
+	if string(expected) != got {
+		return errors.New("transcript does not match golden file (run tests with -wsmock.update to rewrite it):\n" + diffLines(string(expected), got))
+	}
+	return nil
+}
+
+// API
+
+// Compares the transcript of the current round (messages sent to the conn and written by its handler, in order,
+// with their message type) to the golden file at path, or rewrites the golden file if tests are run with -wsmock.update.
+// Errors are reported on t.
+//
+// Messages are formatted as JSON when possible, and the fields selected by the JSONPath masks (like `$.id`,
+// `$.payload.users[*].joinedAt` or `$..timestamp`) are replaced by "<masked>", to ignore volatile values.
+//
+// The comparison happens when the round ends: RunAssertions then waits until the timeout is reached (or the conn
+// is closed) to record the full transcript.
+func (r *Recorder) MatchSnapshot(t *testing.T, path string, masks ...string) {
+	r.currentRound.snapshots = append(r.currentRound.snapshots, snapshot{t, path, masks})
+	r.keepEventsOfRound()
+}
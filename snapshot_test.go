@@ -0,0 +1,126 @@
+package wsmock
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONPathMask(t *testing.T) {
+	cases := []struct {
+		path, want string
+	}{
+		{"$.id", `{"id":"<masked>","payload":{"at":1,"users":[{"at":2,"name":"a"},{"at":3,"name":"b"}]}}`},
+		{"$.payload.users[*].at", `{"id":"x","payload":{"at":1,"users":[{"at":"<masked>","name":"a"},{"at":"<masked>","name":"b"}]}}`},
+		{"$.payload.users[1]", `{"id":"x","payload":{"at":1,"users":[{"at":2,"name":"a"},"<masked>"]}}`},
+		{"$['payload'].at", `{"id":"x","payload":{"at":"<masked>","users":[{"at":2,"name":"a"},{"at":3,"name":"b"}]}}`},
+		{"$..at", `{"id":"x","payload":{"at":"<masked>","users":[{"at":"<masked>","name":"a"},{"at":"<masked>","name":"b"}]}}`},
+		{"$.missing", `{"id":"x","payload":{"at":1,"users":[{"at":2,"name":"a"},{"at":3,"name":"b"}]}}`},
+	}
+	for _, c := range cases {
+		p, err := parseJSONPath(c.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := `{"id":"x","payload":{"at":1,"users":[{"name":"a","at":2},{"name":"b","at":3}]}}`
+		if got := formatContent(m, []jsonPath{p}); got != c.want {
+			t.Errorf("%v:\n got %v\nwant %v", c.path, got, c.want)
+		}
+	}
+
+	for _, invalid := range []string{"id", "$", "$.a[", "$.a[x]", "$.."} {
+		if _, err := parseJSONPath(invalid); err == nil {
+			t.Errorf("%q should be invalid", invalid)
+		}
+	}
+}
+
+func TestFormatContent(t *testing.T) {
+	cases := []struct {
+		m    any
+		want string
+	}{
+		{"pong", `"pong"`},
+		{`{"b":1,"a":2}`, `{"a":2,"b":1}`},
+		{[]byte{0, 255}, "00ff"},
+		{struct{ Kind string }{"chat"}, `{"Kind":"chat"}`},
+	}
+	for _, c := range cases {
+		if got := formatContent(c.m, nil); got != c.want {
+			t.Errorf("formatContent(%#v) = %v", c.m, got)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines("a\nb\nc\n", "a\nc\nd\n")
+	want := "\t  a\n\t- b\n\t  c\n\t+ d\n"
+	if got != want {
+		t.Errorf("unexpected diff:\n%v", got)
+	}
+}
+
+func TestMatchSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "flow.golden")
+	run := func(reply string) *testing.T {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		go func() {
+			var m any
+			conn.ReadJSON(&m)
+			conn.WriteJSON(map[string]any{"reply": reply, "at": time.Now().UnixNano()})
+		}()
+		conn.Send("ping")
+		rec.MatchSnapshot(mockT, path, "$.at")
+		rec.RunAssertions(20 * time.Millisecond)
+		return mockT
+	}
+
+	t.Run("fails when golden file does not exist", func(t *testing.T) {
+		if !run("pong").Failed() {
+			t.Error("snapshot should fail")
+		}
+	})
+
+	t.Run("does not define a generic update flag", func(t *testing.T) {
+		if flag.Lookup("update") != nil || flag.Lookup("wsmock.update") == nil {
+			t.Error("snapshot flag should be namespaced")
+		}
+	})
+
+	t.Run("writes golden file with -wsmock.update", func(t *testing.T) {
+		*updateSnapshots = true
+		defer func() { *updateSnapshots = false }()
+
+		if run("pong").Failed() {
+			t.Fatal("snapshot should be written")
+		}
+		b, _ := os.ReadFile(path)
+		want := "send  text   \"ping\"\nwrite json   {\"at\":\"<masked>\",\"reply\":\"pong\"}\n"
+		if string(b) != want {
+			t.Errorf("unexpected golden file:\n%v", string(b))
+		}
+	})
+
+	t.Run("succeeds when transcript matches", func(t *testing.T) {
+		if run("pong").Failed() {
+			t.Error("snapshot should succeed")
+		}
+	})
+
+	t.Run("fails when transcript differs", func(t *testing.T) {
+		if !run("pang").Failed() {
+			t.Error("snapshot should fail")
+		}
+	})
+}
+
+func TestCheckSnapshotError(t *testing.T) {
+	err := snapshot{path: "missing.golden", masks: []string{"id"}}.check(nil)
+	if err == nil || !strings.Contains(err.Error(), "should start with $") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package wsmock
+
+import "fmt"
+
+// Returns the indexes of the messages explained by the previous conditions of thread t and by its condition,
+// once it passed.
+//
+// Conditions explain the message they pass on, except:
+// - All* conditions explain all the messages they were tried on
+// - Last* conditions explain the last message
+// - Unordered conditions explain the messages they matched
+// - None* conditions explain nothing
+func (j *assertionJob) explain(t *thread, end bool) []int {
+	explained := append([]int(nil), t.explained...)
+	n := len(j.writes)
+	c := t.c
+	if w, ok := c.(*within); ok {
+		c = w.Condition
+	}
+	switch c := c.(type) {
+	case *noneTo:
+	case *allTo:
+		for i := t.start; i < n; i++ {
+			explained = append(explained, i)
+		}
+	case *lastTo:
+		if n > t.start {
+			explained = append(explained, n-1)
+		}
+	case *setTo:
+		for _, m := range c.matchOf {
+			if m != -1 {
+				explained = append(explained, t.start+m)
+			}
+		}
+	default:
+		if !end && !j.prog[t.pc].skip {
+			explained = append(explained, n-1)
+		}
+	}
+	return explained
+}
+
+// Enables strict mode: each round waits until the RunAssertions timeout is reached or the conn is closed,
+// and fails if some messages written during the round are not explained by a condition of a successful assertion.
+//
+// For instance `OneToBe("a")` explains the message it passes on but not the messages it skipped, so with strict mode
+// `rec.NewAssertion().OneToBe("a").OneToBe("b")` fails if `a debug b` is received.
+func (r *Recorder) Strict() {
+	r.strict = true
+}
+
+// Reports messages of the round that are not explained by any assertion (when the round did not already fail).
+func (r *Recorder) checkStrict() {
+	r.mu.RLock()
+	failed := len(r.errors) > r.currentRound.errorsAt
+	r.mu.RUnlock()
+	if failed {
+		return
+	}
+
+	explained := make(map[int]bool)
+	for _, j := range r.currentRound.jobs {
+		for _, i := range j.explained {
+			explained[j.position(i)] = true
+		}
+	}
+	records := r.currentRound.log.records
+	output := ""
+	count := 0
+	for i, rec := range records {
+		if !explained[i] {
+			count++
+			output += fmt.Sprintf("\tmessage#%v [%v] %v\n", i, relativeTime(rec.Time, r.currentRound.since), r.format(rec.Message))
+		}
+	}
+	if count > 0 {
+		intro := fmt.Sprintf("\nIn %v → strict mode, %v/%v message(s) not explained by any condition:\n", r.name, count, len(records))
+		r.addError(intro + output)
+	}
+}
@@ -0,0 +1,91 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrict(t *testing.T) {
+	t.Run("lists unexplained messages with their positions", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		go func() {
+			conn.WriteJSON("a")
+			conn.WriteJSON("debug")
+			conn.WriteJSON("b")
+			conn.WriteJSON("debug")
+		}()
+		rec.NewAssertion().OneToBe("a").OneToBe("b")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) != 1 {
+			t.Fatalf("unexpected errors: %v", rec.errors)
+		}
+		err := rec.errors[0]
+		if !strings.Contains(err, "2/4 message(s) not explained") || !strings.Contains(err, `message#1 [`) || !strings.Contains(err, `message#3 [`) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if strings.Contains(err, "message#0") || strings.Contains(err, "message#2") {
+			t.Errorf("explained messages should not be listed: %v", err)
+		}
+	})
+
+	t.Run("records messages written right before close", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+		rec.BeginRound()
+
+		// the conn is closed while writes are pending, and after jobs are finished
+		conn.WriteJSON("debug")
+		conn.Close()
+		rec.currentRound.end(rec, nil, true)
+
+		if records := rec.currentRound.log.records; len(records) != 1 || records[0].Message != "debug" {
+			t.Errorf("unexpected records: %+v", records)
+		}
+		rec.checkStrict()
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "1/1 message(s) not explained") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+
+	t.Run("does not report when an assertion failed", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		go conn.WriteJSON("debug")
+		rec.NewAssertion().OneToBe("a")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) != 2 || strings.Contains(rec.errors[0]+rec.errors[1], "strict mode") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+
+	t.Run("reports leaks in rounds following a failed one", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Strict()
+
+		go conn.WriteJSON("debug")
+		rec.NewAssertion().OneToBe("a")
+		rec.RunAssertions(50 * time.Millisecond)
+		failed := len(rec.errors)
+
+		go func() {
+			conn.WriteJSON("b")
+			conn.WriteJSON("leak")
+		}()
+		rec.NewAssertion().OneToBe("b")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(rec.errors) != failed+1 || !strings.Contains(rec.errors[failed], "1/2 message(s) not explained") {
+			t.Errorf("unexpected errors: %v", rec.errors[failed:])
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package wsmock
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// A KeyFunc extracts a key from a message, used to route it to a sub-recorder (see Recorder.Channel).
+// It returns false if the message has no key.
+type KeyFunc func(m any) (key string, ok bool)
+
+// Returns a KeyFunc that extracts the given field from messages, as long as it is a string. Messages that are not
+// maps are decoded like in typed recorders (see As).
+//
+// For instance `JSONKey("channel")` routes `{"channel": "news", "text": "hello"}` to the "news" sub-recorder.
+func JSONKey(field string) KeyFunc {
+	return func(m any) (string, bool) {
+		fields, ok := m.(map[string]any)
+		if !ok {
+			data, err := jsonData(m)
+			if err != nil || json.Unmarshal(data, &fields) != nil {
+				return "", false
+			}
+		}
+		key, ok := fields[field].(string)
+		return key, ok
+	}
+}
+
+// Routes messages written from now on to sub-recorders, depending on the key returned by f. Messages are still
+// recorded by r, and messages without key are only recorded by r.
+func (r *Recorder) Channel(f KeyFunc) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	r.keyFunc = f
+}
+
+// Returns the sub-recorder of the given key (see Channel), creating it if needed.
+//
+// A sub-recorder is a Recorder with its own assertions and history, made of the messages routed to it.
+// Its assertions are run when the assertions of its parent are run (with r.RunAssertions or wsmock.RunAssertions),
+// and its name in logs is the name of its parent followed by the key, like `recorder#0/news`.
+func (r *Recorder) Sub(key string) *Recorder {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	return r.sub(key)
+}
+
+// the caller must hold subMu
+func (r *Recorder) sub(key string) *Recorder {
+	if s, ok := r.subs[key]; ok {
+		return s
+	}
+	s := &Recorder{
+		t:        r.t,
+		index:    r.index,
+		name:     r.name + "/" + key,
+		clock:    r.clock,
+		timeline: r.timeline,
+		doneCh:   make(chan struct{}),
+		notifyCh: make(chan struct{}, 1),
+		parent:   r,
+	}
+	r.roundMu.Lock()
+	s.policy, s.open, s.begun = r.policy, r.open, r.begun
+	r.roundMu.Unlock()
+	s.session = newSessionRound(r.clock.Now())
+	s.resetRound()
+	if r.isDone() {
+		s.stop()
+	}
+	if r.subs == nil {
+		r.subs = make(map[string]*Recorder)
+	}
+	r.subs[key] = s
+	r.subKeys = append(r.subKeys, key)
+	return s
+}
+
+func (r *Recorder) getSubs() []*Recorder {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	subs := make([]*Recorder, len(r.subKeys))
+	for i, key := range r.subKeys {
+		subs[i] = r.subs[key]
+	}
+	return subs
+}
+
+// records w on the sub-recorder matching its key, if any (seq being the position of w in the timeline)
+func (r *Recorder) route(w Record, seq int) {
+	r.subMu.Lock()
+	if r.keyFunc == nil {
+		r.subMu.Unlock()
+		return
+	}
+	key, ok := r.keyFunc(w.Message)
+	var s *Recorder
+	if ok {
+		s = r.sub(key)
+	}
+	r.subMu.Unlock()
+
+	if s != nil {
+		s.timeline.addAt(seq, s, w)
+		s.addToHistory(w)
+		s.push(w)
+		s.route(w, seq)
+	}
+}
+
+// runs the assertions of sub-recorders, the returned WaitGroup is done when they are finished
+func (r *Recorder) runSubs(timeout time.Duration) *sync.WaitGroup {
+	wg := &sync.WaitGroup{}
+	for _, s := range r.getSubs() {
+		if s.idle() {
+			s.RunAssertions(timeout)
+			continue
+		}
+		wg.Add(1)
+		go func(s *Recorder) {
+			defer wg.Done()
+			s.RunAssertions(timeout)
+		}(s)
+	}
+	return wg
+}
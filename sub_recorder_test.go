@@ -0,0 +1,102 @@
+package wsmock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestJSONKey(t *testing.T) {
+	type event struct {
+		Channel string `json:"channel"`
+	}
+	key := JSONKey("channel")
+
+	if k, ok := key(event{"news"}); !ok || k != "news" {
+		t.Errorf("JSONKey should extract field from struct, got: %v %v", k, ok)
+	}
+	if k, ok := key(map[string]any{"channel": "news"}); !ok || k != "news" {
+		t.Errorf("JSONKey should extract field from map, got: %v %v", k, ok)
+	}
+	if _, ok := key(map[string]any{"channel": 1}); ok {
+		t.Error("JSONKey should ignore non string fields")
+	}
+	if _, ok := key("news"); ok {
+		t.Error("JSONKey should ignore messages that are not objects")
+	}
+	if k, ok := key(`{"channel":"news"}`); !ok || k != "news" {
+		t.Errorf("JSONKey should extract field from JSON text, got: %v %v", k, ok)
+	}
+	if k, ok := key([]byte(`{"channel":"news"}`)); !ok || k != "news" {
+		t.Errorf("JSONKey should extract field from JSON binary, got: %v %v", k, ok)
+	}
+}
+
+func TestSub(t *testing.T) {
+	t.Run("routes messages written with WriteMessage", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Channel(JSONKey("channel"))
+
+		go conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"news"}`))
+		rec.Sub("news").NewAssertion().OneToBe(`{"channel":"news"}`)
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if mockT.Failed() {
+			t.Errorf("unexpected errors: %v", rec.Sub("news").errors)
+		}
+	})
+
+	t.Run("stops sub-recorders created while the recorder stops", func(t *testing.T) {
+		_, rec := NewGorillaMockAndRecorder(&testing.T{})
+		stopped := make(chan struct{})
+		go func() {
+			rec.stop()
+			close(stopped)
+		}()
+		for i := 0; i < 10; i++ {
+			rec.Sub(fmt.Sprint(i))
+		}
+		<-stopped
+		for _, sub := range rec.getSubs() {
+			if !sub.isDone() {
+				t.Error("sub-recorder should be stopped")
+			}
+		}
+	})
+
+	t.Run("returns the same sub-recorder for a key", func(t *testing.T) {
+		_, rec := NewGorillaMockAndRecorder(&testing.T{})
+		if rec.Sub("news") != rec.Sub("news") || rec.Sub("news") == rec.Sub("sports") {
+			t.Error("Sub should return one sub-recorder per key")
+		}
+	})
+
+	t.Run("names sub-recorders after their parent in logs", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		rec.Channel(JSONKey("channel"))
+
+		go conn.WriteJSON(map[string]any{"channel": "news"})
+		sub := rec.Sub("news")
+		sub.NewAssertion().NextToBe("bye")
+		rec.RunAssertions(50 * time.Millisecond)
+
+		if len(sub.errors) == 0 || !strings.Contains(sub.errors[0], "In recorder#0/news → assertion#0") {
+			t.Errorf("unexpected errors: %v", sub.errors)
+		}
+	})
+
+	t.Run("stops sub-recorders with their parent", func(t *testing.T) {
+		conn, rec := NewGorillaMockAndRecorder(&testing.T{})
+		before := rec.Sub("news")
+		conn.Close()
+		after := rec.Sub("sports")
+		if !before.done || !after.done {
+			t.Error("sub-recorders should be stopped when the conn is closed")
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package wsmock
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The timeline gathers the messages written to all the recorders of a test, in a global order.
+type timeline struct {
+	mu      sync.Mutex
+	since   time.Time // used to print relative timestamps in logs
+	seq     int
+	entries []timelineEntry
+	checks  []timelineCheck
+}
+
+type timelineEntry struct {
+	seq int
+	rec *Recorder
+	Record
+}
+
+// returns an error if the check fails
+type timelineCheck func(entries []timelineEntry) (err string)
+
+func newTimeline(since time.Time) *timeline {
+	return &timeline{since: since}
+}
+
+// adds a message written to rec and returns its position (messages are only kept while checks are registered)
+func (tl *timeline) add(rec *Recorder, w Record) (seq int) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	seq = tl.seq
+	tl.seq++
+	if len(tl.checks) > 0 {
+		tl.entries = append(tl.entries, timelineEntry{seq, rec, w})
+	}
+	return
+}
+
+// adds a message routed to a sub-recorder, at the position of the original message
+func (tl *timeline) addAt(seq int, rec *Recorder, w Record) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if len(tl.checks) > 0 {
+		tl.entries = append(tl.entries, timelineEntry{seq, rec, w})
+	}
+}
+
+func (tl *timeline) addCheck(c timelineCheck) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	tl.checks = append(tl.checks, c)
+}
+
+// runs checks on the messages recorded so far, and resets the timeline
+func (tl *timeline) run(now time.Time) (errors []string) {
+	tl.mu.Lock()
+	entries, checks, since := tl.entries, tl.checks, tl.since
+	tl.entries, tl.checks, tl.since = nil, nil, now
+	tl.mu.Unlock()
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	for _, c := range checks {
+		if err := c(entries); err != "" {
+			errors = append(errors, formatTimelineError(entries, since, err))
+		}
+	}
+	return
+}
+
+func formatTimelineError(entries []timelineEntry, since time.Time, err string) string {
+	output := fmt.Sprintf("\nIn timeline, %v message(s) received:\n", len(entries))
+	for _, e := range entries {
+		output += fmt.Sprintf("\t#%v [%v] %v: %v\n", e.seq, relativeTime(e.Time, since), e.rec.name, e.rec.format(e.Message))
+	}
+	return output + "Error occured on end:\n\t" + err + "\n"
+}
+
+func lookupTimeline(t *testing.T) (*timeline, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	tl, ok := store.timelines[t]
+	return tl, ok
+}
+
+// returns a channel receiving when timeline assertions of t can be run (nil if there is none): like None*
+// conditions, they need to wait until the timeout is reached
+func startTimeline(t *testing.T, timeout time.Duration) <-chan time.Time {
+	tl, ok := lookupTimeline(t)
+	if !ok {
+		return nil
+	}
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if len(tl.checks) == 0 {
+		return nil
+	}
+	return getClock(t).After(timeout)
+}
+
+// runs the timeline assertions of t (called by RunAssertions once all recorders are done and end receives)
+func runTimeline(t *testing.T, end <-chan time.Time) {
+	t.Helper()
+
+	tl, ok := lookupTimeline(t)
+	if !ok {
+		return
+	}
+	if end != nil {
+		<-end
+	}
+	for _, err := range tl.run(getClock(t).Now()) {
+		t.Error(err)
+	}
+}
+
+// API
+
+// A TimelineAssertion defines assertions about the order of messages written to several recorders of the same test.
+//
+// Timeline assertions are evaluated by wsmock.RunAssertions(t, timeout), once the timeout is reached and the
+// assertions of all recorders are finished, on the messages written since the first timeline assertion was added
+// (messages are not kept otherwise): timeline assertions should then be added before the script is started.
+type TimelineAssertion struct {
+	tl *timeline
+}
+
+// A TimelineExpectation is the first part of an ordering assertion, completed with Before or After.
+type TimelineExpectation struct {
+	tl  *timeline
+	rec *Recorder
+	f   Predicate
+}
+
+// Initializes a TimelineAssertion on all the recorders of t
+func Timeline(t *testing.T) *TimelineAssertion {
+	return &TimelineAssertion{getTimeline(t, getClock(t))}
+}
+
+// Selects the first message written to rec that checks the Predicate
+func (ta *TimelineAssertion) Expect(rec *Recorder, f Predicate) *TimelineExpectation {
+	return &TimelineExpectation{ta.tl, rec, f}
+}
+
+// returns the position of the first message written to rec that checks f
+func firstIn(entries []timelineEntry, rec *Recorder, f Predicate) (seq int, ok bool) {
+	for _, e := range entries {
+		if e.rec == rec && f(e.Message) {
+			return e.seq, true
+		}
+	}
+	return 0, false
+}
+
+func (e *TimelineExpectation) order(label string, other *Recorder, f Predicate, before bool) {
+	e.tl.addCheck(func(entries []timelineEntry) string {
+		seq, ok := firstIn(entries, e.rec, e.f)
+		if !ok {
+			return fmt.Sprintf("[%v] no message on %v checks predicate: %v", label, e.rec.name, getFunctionName(e.f))
+		}
+		otherSeq, ok := firstIn(entries, other, f)
+		if !ok {
+			return fmt.Sprintf("[%v] no message on %v checks predicate: %v", label, other.name, getFunctionName(f))
+		}
+		if (seq < otherSeq) != before {
+			return fmt.Sprintf(
+				"[%v] message #%v on %v checking predicate: %v\n\tis not %v message #%v on %v checking predicate: %v",
+				label, seq, e.rec.name, getFunctionName(e.f), strings.ToLower(label), otherSeq, other.name, getFunctionName(f),
+			)
+		}
+		return ""
+	})
+}
+
+// Succeeds if the expected message is written before the first message written to rec that checks the Predicate
+func (e *TimelineExpectation) Before(rec *Recorder, f Predicate) {
+	e.order("Before", rec, f, true)
+}
+
+// Succeeds if the expected message is written after the first message written to rec that checks the Predicate
+func (e *TimelineExpectation) After(rec *Recorder, f Predicate) {
+	e.order("After", rec, f, false)
+}
+
+// returns the messages written to rec
+func messagesTo(entries []timelineEntry, rec *Recorder) (messages []any) {
+	for _, e := range entries {
+		if e.rec == rec {
+			messages = append(messages, e.Message)
+		}
+	}
+	return
+}
+
+// returns the messages of ms that are also in others (according to reflect.DeepEqual)
+func common(ms, others []any) (kept []any) {
+	for _, m := range ms {
+		for _, o := range others {
+			if reflect.DeepEqual(m, o) {
+				kept = append(kept, m)
+				break
+			}
+		}
+	}
+	return
+}
+
+// Succeeds if the messages written to several of the recorders are written in the same order to each of them
+// (messages are compared with reflect.DeepEqual, and messages written to only one recorder are ignored).
+func (ta *TimelineAssertion) SameOrder(recs ...*Recorder) {
+	ta.tl.addCheck(func(entries []timelineEntry) string {
+		for i := 0; i < len(recs); i++ {
+			for j := i + 1; j < len(recs); j++ {
+				mi, mj := messagesTo(entries, recs[i]), messagesTo(entries, recs[j])
+				ci, cj := common(mi, mj), common(mj, mi)
+				if !reflect.DeepEqual(ci, cj) {
+					return fmt.Sprintf(
+						"[SameOrder] messages are not in the same order on %v and %v:\n\t\t%#v\n\t\t%#v",
+						recs[i].name, recs[j].name, ci, cj,
+					)
+				}
+			}
+		}
+		return ""
+	})
+}
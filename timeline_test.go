@@ -0,0 +1,60 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	t.Run("orders messages of all recorders", func(t *testing.T) {
+		mockT := &testing.T{}
+		_, rec0 := NewGorillaMockAndRecorder(mockT)
+		_, rec1 := NewGorillaMockAndRecorder(mockT)
+		rec0.Channel(func(m any) (string, bool) { return "sub", true })
+		Timeline(mockT).SameOrder(rec0, rec1)
+
+		rec0.record("a")
+		rec1.record("b")
+		rec0.record("c")
+
+		tl := store.timelines[mockT]
+		if tl != rec1.timeline || len(tl.entries) != 5 {
+			t.Fatalf("unexpected timeline: %+v", tl)
+		}
+		if tl.entries[1].rec != rec0.Sub("sub") || tl.entries[1].seq != 0 || tl.entries[2].seq != 1 {
+			t.Errorf("routed messages should share the position of the original one: %+v", tl.entries)
+		}
+	})
+
+	t.Run("explains failures and resets", func(t *testing.T) {
+		mockT := &testing.T{}
+		_, rec0 := NewGorillaMockAndRecorder(mockT)
+		_, rec1 := NewGorillaMockAndRecorder(mockT)
+
+		Timeline(mockT).Expect(rec0, eq("broadcast")).Before(rec1, eq("ack"))
+		rec1.record("ack")
+		rec0.record("broadcast")
+
+		errors := rec0.timeline.run(time.Now())
+		if len(errors) != 1 || !strings.Contains(errors[0], "[Before] message #1 on recorder#0") || !strings.Contains(errors[0], "is not before message #0 on recorder#1") {
+			t.Errorf("unexpected errors: %v", errors)
+		}
+		if len(rec0.timeline.entries) != 0 || len(rec0.timeline.checks) != 0 {
+			t.Error("timeline should be reset after run")
+		}
+	})
+
+	t.Run("does not keep messages without checks", func(t *testing.T) {
+		mockT := &testing.T{}
+		_, rec := NewGorillaMockAndRecorder(mockT)
+
+		rec.record("a")
+		Timeline(mockT).SameOrder(rec)
+		rec.record("b")
+
+		if tl := rec.timeline; len(tl.entries) != 1 || tl.entries[0].seq != 1 {
+			t.Errorf("unexpected entries: %+v", tl.entries)
+		}
+	})
+}
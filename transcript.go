@@ -0,0 +1,269 @@
+package wsmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Kinds of the events of a transcript
+const (
+	sendEvent  = "send"  // message sent to the conn (by the client)
+	readEvent  = "read"  // message read by the handler
+	writeEvent = "write" // message written by the handler
+	closeEvent = "close" // conn closed
+)
+
+// Frame types of the messages of a transcript
+const (
+	jsonFrame   = "json"   // Go value, JSON-encoded (WriteJSON or Send)
+	textFrame   = "text"   // text message
+	binaryFrame = "binary" // binary message
+	pingFrame   = "ping"
+	pongFrame   = "pong"
+	closeFrame  = "close"
+)
+
+// orders events across recorders
+var eventSeq atomic.Int64
+
+// default number of events kept per conn (see Recorder.SetTranscriptLimit)
+const defaultTranscriptLimit = 10000
+
+// An event happened on a conn (see event kinds), in the order they happened
+type event struct {
+	seq   int64
+	kind  string
+	frame string // see frame types
+	Record
+}
+
+// data events are messages sent to the conn and written by its handler (not read, close nor control frames)
+func (e event) isData() bool {
+	return (e.kind == sendEvent || e.kind == writeEvent) && (e.frame == jsonFrame || e.frame == textFrame || e.frame == binaryFrame)
+}
+
+func (r *Recorder) addEvent(kind, frame string, w Record) {
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	r.events = append(r.events, event{eventSeq.Add(1), kind, frame, w})
+	r.trimEvents()
+}
+
+// drops the oldest events beyond the limit, except the events of the current round if a snapshot needs them
+// (must be called with eventsMu held)
+func (r *Recorder) trimEvents() {
+	if r.eventsLimit <= 0 || len(r.events) <= r.eventsLimit {
+		return
+	}
+	drop := len(r.events) - r.eventsLimit
+	if r.keepRoundEvents && drop > r.roundEvents {
+		drop = r.roundEvents
+	}
+	r.events = r.events[drop:]
+	r.roundEvents = max(r.roundEvents-drop, 0)
+}
+
+func (r *Recorder) getEvents() []event {
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	return append([]event(nil), r.events...)
+}
+
+// returns the data events since the beginning of the current round
+func (r *Recorder) getRoundEvents() []event {
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	var events []event
+	for _, e := range r.events[r.roundEvents:] {
+		if e.isData() {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (r *Recorder) markRoundEvents() {
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	r.roundEvents = len(r.events)
+	r.keepRoundEvents = false
+	r.trimEvents()
+}
+
+// keeps the events of the current round whatever the limit (see MatchSnapshot)
+func (r *Recorder) keepEventsOfRound() {
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	r.keepRoundEvents = true
+}
+
+// returns the frame type of a message given to Send
+func (r *Recorder) sendFrame(m any) string {
+	switch m.(type) {
+	case string:
+		return textFrame
+	case []byte:
+		return binaryFrame
+	}
+	if c := r.getCodec(); c != nil && c.MessageType() == websocket.BinaryMessage {
+		return binaryFrame
+	}
+	return jsonFrame
+}
+
+// returns the frame type of a control message
+func controlFrameType(messageType int) string {
+	switch messageType {
+	case websocket.PingMessage:
+		return pingFrame
+	case websocket.PongMessage:
+		return pongFrame
+	}
+	return closeFrame
+}
+
+func transcriptOf(r *Recorder, events []event) TranscriptEvents {
+	transcript := make(TranscriptEvents, len(events))
+	for i, e := range events {
+		transcript[i] = TranscriptEvent{e.Time, r.name, e.kind, e.frame, e.Message}
+	}
+	return transcript
+}
+
+// API
+
+// A TranscriptEvent describes something that happened on a conn: a message sent by the client ("send"), read by
+// the handler ("read") or written by it ("write"), or the conn being closed ("close").
+//
+// Type is the message type: "json" (Go value, JSON-encoded), "text", "binary", or a control frame ("ping", "pong"
+// or "close").
+type TranscriptEvent struct {
+	Time    time.Time `json:"time"`
+	Conn    string    `json:"conn"`
+	Event   string    `json:"event"`
+	Type    string    `json:"type,omitempty"`
+	Message any       `json:"message,omitempty"`
+}
+
+// Ordered events of one or several conns
+type TranscriptEvents []TranscriptEvent
+
+// Returns the events that happened on the conn of the recorder since its creation (only the latest ones are kept,
+// see SetTranscriptLimit)
+func (r *Recorder) Transcript() TranscriptEvents {
+	return transcriptOf(r, r.getEvents())
+}
+
+// Sets the number of events kept for the transcript of the conn (default is 10,000): older events are dropped,
+// except the ones of the current round if it has snapshots (see MatchSnapshot). A limit of 0 keeps all events.
+func (r *Recorder) SetTranscriptLimit(n int) {
+	r.eventsMu.Lock()
+	defer r.eventsMu.Unlock()
+
+	r.eventsLimit = n
+	r.trimEvents()
+}
+
+// Returns the events that happened on the conns of the recorders created with t, in order
+func Transcript(t *testing.T) TranscriptEvents {
+	type ordered struct {
+		seq int64
+		TranscriptEvent
+	}
+	var all []ordered
+	for _, r := range getIndexedRecorders(t) {
+		for _, e := range r.getEvents() {
+			all = append(all, ordered{e.seq, TranscriptEvent{e.Time, r.name, e.kind, e.frame, e.Message}})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].seq < all[j].seq })
+	transcript := make(TranscriptEvents, len(all))
+	for i, o := range all {
+		transcript[i] = o.TranscriptEvent
+	}
+	return transcript
+}
+
+// Writes the events in the JSON Lines format (one JSON object per line). Messages that can't be JSON-encoded are
+// written as their Go representation.
+func (ts TranscriptEvents) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range ts {
+		if _, err := json.Marshal(e.Message); err != nil {
+			e.Message = fmt.Sprintf("%#v", e.Message)
+		}
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the transcript of the test (see Transcript) as JSON Lines to the file at path, when the test is over
+// and only if it failed (useful to attach transcripts to CI artifacts). It may be called before or after the
+// conns of t are created, and session assertions (see Recorder.Session) are evaluated before the test failure
+// is checked.
+func SaveTranscriptOnFailure(t *testing.T, path string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if len(store.index[t]) == 0 && len(store.transcripts[t]) == 0 { // in case no conn is created
+		t.Cleanup(func() {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+
+			delete(store.transcripts, t)
+		})
+	}
+	store.transcripts[t] = append(store.transcripts[t], path)
+}
+
+// writes the transcripts of t if it failed (see SaveTranscriptOnFailure), called when t is over
+func saveTranscripts(t *testing.T) {
+	t.Helper()
+
+	store.mu.Lock()
+	paths := store.transcripts[t]
+	delete(store.transcripts, t)
+	store.mu.Unlock()
+
+	if len(paths) == 0 || !t.Failed() {
+		return
+	}
+	transcript := Transcript(t)
+	for _, path := range paths {
+		if err := saveTranscript(transcript, path); err != nil {
+			t.Errorf("[wsmock] transcript can't be saved: %v", err)
+		} else {
+			t.Logf("[wsmock] transcript saved to %v", path)
+		}
+	}
+}
+
+func saveTranscript(ts TranscriptEvents, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return ts.WriteJSONL(f)
+}
@@ -0,0 +1,158 @@
+package wsmock
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestTranscript(t *testing.T) {
+	t.Run("lists events of a conn in order", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		conn.Send("ping")
+		conn.ReadMessage()
+		conn.WriteJSON(map[string]any{"kind": "pong"})
+		conn.WriteMessage(websocket.BinaryMessage, []byte{1})
+		conn.WriteControl(websocket.PingMessage, []byte("keepalive"), time.Time{})
+		conn.Send("bye")
+		conn.ReadMessage() // handles the pong first
+		conn.Close()
+
+		var got []string
+		for _, e := range rec.Transcript() {
+			got = append(got, e.Event+" "+e.Type)
+			if e.Conn != "recorder#0" || e.Time.IsZero() {
+				t.Errorf("unexpected event: %+v", e)
+			}
+		}
+		want := []string{"send text", "read text", "write json", "write binary", "write ping", "send text", "read pong", "read text", "close "}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("unexpected events:\n got %v\nwant %v", got, want)
+		}
+	})
+
+	t.Run("merges events of the conns of a test", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn0, _ := NewGorillaMockAndRecorder(mockT)
+		conn1, _ := NewGorillaMockAndRecorder(mockT)
+
+		conn0.WriteJSON("a")
+		conn1.WriteJSON("b")
+		conn0.WriteJSON("c")
+
+		var got []string
+		for _, e := range Transcript(mockT) {
+			got = append(got, e.Conn+":"+e.Message.(string))
+		}
+		if strings.Join(got, ",") != "recorder#0:a,recorder#1:b,recorder#0:c" {
+			t.Errorf("unexpected events: %v", got)
+		}
+	})
+}
+
+func TestSaveTranscriptOnFailure(t *testing.T) {
+	t.Run("saves transcript when called before conns are created", func(t *testing.T) {
+		mockT := &testing.T{}
+		path := filepath.Join(t.TempDir(), "transcript.jsonl")
+		SaveTranscriptOnFailure(mockT, path)
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		conn.WriteJSON("a")
+		rec.NewAssertion().OneToBe("b")
+		rec.RunAssertions(10 * time.Millisecond)
+		cleanupRecorders(mockT)
+
+		b, err := os.ReadFile(path)
+		if err != nil || !strings.Contains(string(b), `"message":"a"`) {
+			t.Errorf("unexpected transcript: %q, %v", b, err)
+		}
+	})
+
+	t.Run("saves transcript when only session assertions fail", func(t *testing.T) {
+		mockT := &testing.T{}
+		path := filepath.Join(t.TempDir(), "transcript.jsonl")
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+		SaveTranscriptOnFailure(mockT, path)
+
+		conn.WriteJSON("a")
+		rec.Session().OneToBe("b")
+		cleanupRecorders(mockT)
+
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("transcript should be saved: %v", err)
+		}
+	})
+}
+
+func TestSetTranscriptLimit(t *testing.T) {
+	t.Run("keeps the latest events", func(t *testing.T) {
+		conn, rec := NewGorillaMockAndRecorder(&testing.T{})
+		rec.SetTranscriptLimit(2)
+
+		conn.WriteJSON("a")
+		conn.WriteJSON("b")
+		conn.WriteJSON("c")
+
+		ts := rec.Transcript()
+		if len(ts) != 2 || ts[0].Message != "b" || ts[1].Message != "c" {
+			t.Errorf("unexpected transcript: %+v", ts)
+		}
+	})
+
+	t.Run("keeps events of the round for snapshots", func(t *testing.T) {
+		conn, rec := NewGorillaMockAndRecorder(&testing.T{})
+		rec.SetTranscriptLimit(2)
+
+		conn.WriteJSON("a")
+		rec.MatchSnapshot(t, filepath.Join(t.TempDir(), "unused.golden"))
+		conn.WriteJSON("b")
+		conn.WriteJSON("c")
+
+		if events := rec.getRoundEvents(); len(events) != 3 {
+			t.Errorf("unexpected round events: %+v", events)
+		}
+		rec.resetRound()
+		if ts := rec.Transcript(); len(ts) != 2 {
+			t.Errorf("events should be trimmed once the round is over: %+v", ts)
+		}
+	})
+}
+
+func TestWriteJSONL(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	ts := TranscriptEvents{
+		{at, "recorder#0", "send", "json", map[string]any{"kind": "<join>"}},
+		{at, "recorder#0", "write", "json", make(chan int)},
+		{at, "recorder#0", "close", "", nil},
+	}
+	path := filepath.Join(t.TempDir(), "artifacts", "transcript.jsonl")
+	if err := saveTranscript(ts, path); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got:\n%s", b)
+	}
+	if lines[0] != `{"time":"2024-01-02T03:04:05.000000006Z","conn":"recorder#0","event":"send","type":"json","message":{"kind":"<join>"}}` {
+		t.Errorf("unexpected line: %v", lines[0])
+	}
+	if !strings.Contains(lines[1], `"message":"(chan int)`) {
+		t.Errorf("unexpected line: %v", lines[1])
+	}
+	if lines[2] != `{"time":"2024-01-02T03:04:05.000000006Z","conn":"recorder#0","event":"close"}` {
+		t.Errorf("unexpected line: %v", lines[2])
+	}
+
+	var buf bytes.Buffer
+	if err := ts[:1].WriteJSONL(&buf); err != nil || buf.String() != lines[0]+"\n" {
+		t.Errorf("unexpected output: %v %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,204 @@
+package wsmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// A TypedRecorder is a Recorder whose messages are decoded into T before being checked by its assertions,
+// so that predicates and targets are typed (see As).
+type TypedRecorder[T any] struct {
+	*Recorder
+}
+
+// A TypedAssertion is an Assertion of a TypedRecorder: its conditions are tried on messages decoded into T.
+//
+// Conditions that are not available on TypedAssertion can be added to the underlying Assertion (see Assertion),
+// their predicates then receive messages of type T.
+type TypedAssertion[T any] struct {
+	a *Assertion
+}
+
+// Decodes a message into T: messages already of type T are kept, others are unmarshalled from their JSON data
+// (see jsonData).
+func decode[T any](m any) (any, error) {
+	if v, ok := m.(T); ok {
+		return v, nil
+	}
+	data, err := jsonData(m)
+	if err != nil {
+		return nil, decodeError[T](m, err)
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, decodeError[T](m, err)
+	}
+	return v, nil
+}
+
+func decodeError[T any](m any, err error) error {
+	return fmt.Errorf("[As] message (of type %T) can't be decoded into %T: %v\nFailing message: %+v", m, *new(T), err, m)
+}
+
+// converts a typed predicate to a Predicate on decoded messages
+func typed[T any](f func(T) bool) Predicate {
+	return func(msg any) bool {
+		v, ok := msg.(T)
+		return ok && f(v)
+	}
+}
+
+func typedTargets[T any](targets []T) []any {
+	untyped := make([]any, len(targets))
+	for i, target := range targets {
+		untyped[i] = target
+	}
+	return untyped
+}
+
+// API
+
+// Returns a TypedRecorder decoding the messages of r into T, for instance `wsmock.As[Message](rec)` if the
+// handler writes Message structs with WriteJSON (or their JSON encoding with WriteMessage).
+//
+// Messages that can't be decoded make the assertions of the TypedRecorder fail.
+func As[T any](r *Recorder) *TypedRecorder[T] {
+	return &TypedRecorder[T]{r}
+}
+
+// Initialize a new chainable TypedAssertion
+func (r *TypedRecorder[T]) NewAssertion() *TypedAssertion[T] {
+	a := r.Recorder.NewAssertion()
+	a.decode = decode[T]
+	return &TypedAssertion[T]{a}
+}
+
+// Returns the underlying Assertion, to add conditions that are not typed
+func (ta *TypedAssertion[T]) Assertion() *Assertion {
+	return ta.a
+}
+
+// Scopes the assertion to the messages checking f (see Assertion.Filter)
+func (ta *TypedAssertion[T]) Filter(f func(T) bool) *TypedAssertion[T] {
+	ta.a.Filter(typed(f))
+	return ta
+}
+
+// Sets a time window on the last added condition (see Assertion.Within)
+func (ta *TypedAssertion[T]) Within(d time.Duration) *TypedAssertion[T] {
+	ta.a.Within(d)
+	return ta
+}
+
+// Adds a condition that succeeds if no message is received during d, and fails as soon as one is
+func (ta *TypedAssertion[T]) NoneWithin(d time.Duration) *TypedAssertion[T] {
+	ta.a.NoneWithin(d)
+	return ta
+}
+
+// OneTo*
+
+// Adds a condition that succeeds if a new message is equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) OneToBe(target T) *TypedAssertion[T] {
+	ta.a.append(oneToBe(target, deepEq(target)))
+	return ta
+}
+
+// Adds a condition that succeeds if a new message checks f
+func (ta *TypedAssertion[T]) OneToCheck(f func(T) bool) *TypedAssertion[T] {
+	ta.a.OneToCheck(typed(f))
+	return ta
+}
+
+// Adds a condition that succeeds if a new message is not equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) OneNotToBe(target T) *TypedAssertion[T] {
+	ta.a.append(oneNotToBe(target, deepEq(target)))
+	return ta
+}
+
+// Adds a condition that succeeds if a new message does not check f
+func (ta *TypedAssertion[T]) OneNotToCheck(f func(T) bool) *TypedAssertion[T] {
+	ta.a.OneNotToCheck(typed(f))
+	return ta
+}
+
+// NextTo*
+
+// Adds a condition that succeeds if the next message is equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) NextToBe(target T) *TypedAssertion[T] {
+	ta.a.append(nextToBe(target, deepEq(target)))
+	return ta
+}
+
+// Adds a condition that succeeds if the next message checks f
+func (ta *TypedAssertion[T]) NextToCheck(f func(T) bool) *TypedAssertion[T] {
+	ta.a.NextToCheck(typed(f))
+	return ta
+}
+
+// Adds a condition that succeeds if the next message is not equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) NextNotToBe(target T) *TypedAssertion[T] {
+	ta.a.append(nextNotToBe(target, deepEq(target)))
+	return ta
+}
+
+// Adds a condition that succeeds if the next message does not check f
+func (ta *TypedAssertion[T]) NextNotToCheck(f func(T) bool) *TypedAssertion[T] {
+	ta.a.NextNotToCheck(typed(f))
+	return ta
+}
+
+// Unordered
+
+// Adds a condition that succeeds if each target is equal to a distinct new message, in any order
+func (ta *TypedAssertion[T]) SetToBe(targets ...T) *TypedAssertion[T] {
+	ta.a.append(setToBe(typedTargets(targets), deepEq))
+	return ta
+}
+
+// LastTo*
+
+// Adds a condition that succeeds if the last message is equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) LastToBe(target T) {
+	ta.a.append(lastToBe(target, deepEq(target)))
+}
+
+// Adds a condition that succeeds if the last message checks f
+func (ta *TypedAssertion[T]) LastToCheck(f func(T) bool) {
+	ta.a.LastToCheck(typed(f))
+}
+
+// Adds a condition that succeeds if the last message is not equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) LastNotToBe(target T) {
+	ta.a.append(lastNotToBe(target, deepEq(target)))
+}
+
+// Adds a condition that succeeds if the last message does not check f
+func (ta *TypedAssertion[T]) LastNotToCheck(f func(T) bool) {
+	ta.a.LastNotToCheck(typed(f))
+}
+
+// All*
+
+// Adds a condition that succeeds if all remaining messages are equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) AllToBe(target T) {
+	ta.a.append(allToBe(target, deepEq(target)))
+}
+
+// Adds a condition that succeeds if all remaining messages check f
+func (ta *TypedAssertion[T]) AllToCheck(f func(T) bool) {
+	ta.a.AllToCheck(typed(f))
+}
+
+// None*
+
+// Adds a condition that succeeds if no remaining message is equal to target (according to reflect.DeepEqual)
+func (ta *TypedAssertion[T]) NoneToBe(target T) {
+	ta.a.append(noneToBe(target, deepEq(target)))
+}
+
+// Adds a condition that succeeds if no remaining message checks f
+func (ta *TypedAssertion[T]) NoneToCheck(f func(T) bool) {
+	ta.a.NoneToCheck(typed(f))
+}
@@ -0,0 +1,48 @@
+package wsmock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type envelope struct {
+	Kind string `json:"kind"`
+}
+
+func TestDecode(t *testing.T) {
+	cases := []struct {
+		m    any
+		want envelope
+	}{
+		{envelope{"a"}, envelope{"a"}},
+		{`{"kind":"b"}`, envelope{"b"}},
+		{[]byte(`{"kind":"c"}`), envelope{"c"}},
+		{map[string]any{"kind": "d"}, envelope{"d"}},
+	}
+	for _, c := range cases {
+		got, err := decode[envelope](c.m)
+		if err != nil || got != c.want {
+			t.Errorf("decode(%#v) = %#v, %v", c.m, got, err)
+		}
+	}
+
+	if _, err := decode[envelope]("not json"); err == nil || !strings.Contains(err.Error(), "can't be decoded into wsmock.envelope") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTypedRecorder(t *testing.T) {
+	t.Run("reports decoding failures", func(t *testing.T) {
+		mockT := &testing.T{}
+		conn, rec := NewGorillaMockAndRecorder(mockT)
+
+		go conn.WriteJSON(make(chan int))
+		As[envelope](rec).NewAssertion().OneToBe(envelope{"a"})
+		rec.RunAssertions(20 * time.Millisecond)
+
+		if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "[As] message (of type chan int)") || !strings.Contains(rec.errors[0], "Error occured on write") {
+			t.Errorf("unexpected errors: %v", rec.errors)
+		}
+	})
+}